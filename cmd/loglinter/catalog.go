@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/Wladim1r/loglinter/internal/analyzer"
+	"github.com/Wladim1r/loglinter/internal/catalog"
+)
+
+const catalogUsage = `usage: loglinter catalog [-format json|csv] [-o file] [packages]
+       loglinter catalog -diff old.json new.json
+
+Writes every log call of the packages (default ".") with its message, level,
+logger, attribute keys, location and enclosing function. With -diff it
+compares two JSON catalogs and prints the added (+), removed (-) and
+changed (~) messages.
+`
+
+// runCatalog implements the catalog subcommand and returns the exit code.
+func runCatalog(args []string) int {
+	fs := flag.NewFlagSet("catalog", flag.ContinueOnError)
+	format := fs.String("format", catalog.FormatJSON, "output format: json or csv")
+	out := fs.String("o", "", "write the catalog to `file` instead of standard output")
+	diff := fs.Bool("diff", false, "compare two JSON catalogs given as arguments")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), catalogUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != catalog.FormatJSON && *format != catalog.FormatCSV {
+		fmt.Fprintf(os.Stderr, "loglinter: unknown format %q (want json or csv)\n", *format)
+		return 2
+	}
+
+	if *diff && fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	// The output is built in memory so that a failed load or analysis
+	// leaves an existing -o file untouched.
+	var buf bytes.Buffer
+	var err error
+	if *diff {
+		err = diffCatalogs(&buf, fs.Arg(0), fs.Arg(1))
+	} else {
+		err = writeCatalog(&buf, fs.Args(), *format)
+	}
+	if err == nil {
+		if *out != "" {
+			err = os.WriteFile(*out, buf.Bytes(), 0o644)
+		} else {
+			_, err = buf.WriteTo(os.Stdout)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loglinter:", err)
+		return 1
+	}
+	return 0
+}
+
+// writeCatalog loads the packages matching patterns and writes the catalog
+// of their log calls. File names are relative to the working directory when
+// possible.
+func writeCatalog(w io.Writer, patterns []string, format string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax}, patterns...)
+	if err != nil {
+		return err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("packages contain errors")
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{analyzer.CatalogAnalyzer}, pkgs, nil)
+	if err != nil {
+		return err
+	}
+	wd, _ := os.Getwd()
+	var entries []catalog.Entry
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return act.Err
+		}
+		for _, e := range act.Result.([]catalog.Entry) {
+			if rel, err := filepath.Rel(wd, e.File); err == nil && wd != "" {
+				e.File = filepath.ToSlash(rel)
+			}
+			entries = append(entries, e)
+		}
+	}
+	catalog.Sort(entries)
+	return catalog.Write(w, entries, format)
+}
+
+// diffCatalogs prints the changes between two JSON catalogs.
+func diffCatalogs(w io.Writer, oldPath, newPath string) error {
+	from, err := readCatalog(oldPath)
+	if err != nil {
+		return err
+	}
+	to, err := readCatalog(newPath)
+	if err != nil {
+		return err
+	}
+	return catalog.WriteDiff(w, catalog.Diff(from, to))
+}
+
+func readCatalog(path string) ([]catalog.Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := catalog.ReadJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return entries, nil
+}
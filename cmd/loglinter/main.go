@@ -9,16 +9,23 @@
 //	# Lint the current module
 //	loglinter ./...
 //
-//	# Use a custom config file
+//	# Use a custom config file instead of discovering .loglinter.yaml files
 //	loglinter -config /path/to/.loglinter.yaml ./...
 //
 //	# Apply auto-fixes (lowercase rule)
 //	loglinter -fix ./...
+//
+//	# Export a catalog of all log calls, and compare two releases
+//	loglinter catalog -format csv ./...
+//	loglinter catalog -diff v1.json v2.json
 package main
 
 import (
 	"flag"
+	"os"
+	"strings"
 
+	"golang.org/x/tools/go/analysis/multichecker"
 	"golang.org/x/tools/go/analysis/singlechecker"
 
 	"github.com/Wladim1r/loglinter/internal/analyzer"
@@ -26,13 +33,48 @@ import (
 
 var configPath = flag.String(
 	"config",
-	".loglinter.yaml",
-	"path to loglinter YAML configuration file",
+	"",
+	"path to loglinter YAML configuration file (default: discover .loglinter.yaml per package)",
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "catalog" {
+		os.Exit(runCatalog(os.Args[2:]))
+	}
+
 	// singlechecker.Main owns flag parsing, including the built-in -fix flag.
 	// We only declare -config here; its value is read inside the analyzer via
 	// analyzer.NewFlagConfiguredAnalyzer.
-	singlechecker.Main(analyzer.NewFlagConfiguredAnalyzer(configPath))
+	a := analyzer.NewFlagConfiguredAnalyzer(configPath)
+	if !analyzer.CrossPackageRulesEnabled(configFlag(os.Args[1:]), ".") {
+		singlechecker.Main(a)
+		return
+	}
+	// The duplicate and eventid rules exchange package facts, which makes
+	// the driver analyze every dependency; only pay for it when enabled.
+	multichecker.Main(a, analyzer.NewFlagConfiguredCrossPackageAnalyzer(configPath))
+}
+
+// configFlag returns the value of -config among args before flags are
+// parsed, or "" when it is not set.
+func configFlag(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "config" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
 }
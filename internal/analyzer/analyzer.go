@@ -13,12 +13,14 @@ package analyzer
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"os"
 	"path/filepath"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
@@ -35,19 +37,24 @@ func NewAnalyzer(cfg *config.Config) *analysis.Analyzer {
 		cfg = config.DefaultConfig()
 	}
 
-	// Capture cfg in the closure so each Analyzer instance can have its own
-	// configuration – important for tests that create multiple analyzers.
+	// Capture the compiled rules in the closure so each Analyzer instance can
+	// have its own configuration – important for tests that create multiple
+	// analyzers.
+	rs, err := compileRules(cfg)
 	run := func(pass *analysis.Pass) (interface{}, error) {
-		return runPass(pass, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return runPass(pass, rs)
 	}
 
 	return newBaseAnalyzer(run)
 }
 
 // Analyzer is the default singleton analyzer used by the golangci-lint plugin
-// and by default configuration. It reads configuration from .loglinter.yaml in
-// the current working directory.
-var Analyzer = NewAnalyzer(loadConfigOrDefault())
+// and by default configuration. It discovers .loglinter.yaml files for every
+// package, see config.Discover.
+var Analyzer = NewFlagConfiguredAnalyzer(nil)
 
 // NewFlagConfiguredAnalyzer constructs an Analyzer that loads configuration
 // from the provided path flag. It is intended for use by the standalone
@@ -55,32 +62,43 @@ var Analyzer = NewAnalyzer(loadConfigOrDefault())
 // singlechecker.Main.
 //
 // configPath is expected to be a flag.String variable from the main package.
-// The value is read after flags have been parsed.
+// The value is read after flags have been parsed. When it is nil or empty the
+// configuration is discovered per package by walking up from the package
+// directory to the module or workspace root and merging every .loglinter.yaml
+// found on the way. A configuration file that cannot be read or fails
+// validation makes the pass return an error rather than silently linting with
+// defaults.
+//
+// Configuration is loaded and compiled once per run and shared by all passes.
 func NewFlagConfiguredAnalyzer(configPath *string) *analysis.Analyzer {
+	cache := newRuleSetCache()
 	run := func(pass *analysis.Pass) (interface{}, error) {
-		path := ".loglinter.yaml"
+		var (
+			rs  *ruleSet
+			err error
+		)
 		if configPath != nil && *configPath != "" {
-			path = *configPath
+			rs, err = cache.forFiles([]string{*configPath})
+		} else {
+			rs, err = cache.forDir(packageDir(pass))
 		}
-		cfg, err := config.Load(path)
 		if err != nil {
-			// Fall back to defaults if config cannot be loaded; this matches
-			// the behaviour of loadConfigOrDefault used for the plugin.
-			cfg = config.DefaultConfig()
+			return nil, err
 		}
-		return runPass(pass, cfg)
+		return runPass(pass, rs)
 	}
 	return newBaseAnalyzer(run)
 }
 
-// loadConfigOrDefault attempts to load .loglinter.yaml; falls back to defaults.
-func loadConfigOrDefault() *config.Config {
-	cfg, err := config.Load(".loglinter.yaml")
-	if err != nil {
-		// Non-fatal: fall back to defaults and let the user know via stderr.
-		return config.DefaultConfig()
+// packageDir returns the directory containing the package's source files, or
+// "." when it cannot be determined.
+func packageDir(pass *analysis.Pass) string {
+	for _, f := range pass.Files {
+		if tf := pass.Fset.File(f.Pos()); tf != nil && tf.Name() != "" {
+			return filepath.Dir(tf.Name())
+		}
 	}
-	return cfg
+	return "."
 }
 
 // newBaseAnalyzer builds the common Analyzer struct used by all constructors.
@@ -101,37 +119,55 @@ func newBaseAnalyzer(run func(*analysis.Pass) (interface{}, error)) *analysis.An
 type logCall struct {
 	// pos is the source position of the call expression (for diagnostics).
 	pos token.Pos
+	// call is the logging call expression itself.
+	call *ast.CallExpr
+	// args holds the arguments following the message: key/value pairs,
+	// attributes, fields or format operands depending on the logger.
+	args []ast.Expr
 	// msgArg is the AST node of the message argument.
 	msgArg ast.Expr
 	// msgLiteral is the resolved string value of the message, or "" when the
 	// message is a non-constant expression.
 	msgLiteral string
+	// parts holds the operands of the message when it is built by string
+	// concatenation, or the message expression itself.
+	parts []msgPart
 	// fullExpr is the full source text of the message argument, used for the
 	// sensitive-data check so we can inspect variable names.
 	fullExpr string
+	// stack holds the enclosing nodes from the file down to the call itself.
+	stack []ast.Node
 }
 
 // runPass is the main analysis function invoked by go/analysis.
-func runPass(pass *analysis.Pass, cfg *config.Config) (interface{}, error) {
+func runPass(pass *analysis.Pass, rs *ruleSet) (interface{}, error) {
 	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	info := newPkgInfo(pass, rs)
 
 	// We only care about call expressions.
 	nodeFilter := []ast.Node{
 		(*ast.CallExpr)(nil),
 	}
 
-	insp.Preorder(nodeFilter, func(n ast.Node) {
+	insp.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
 		call, ok := n.(*ast.CallExpr)
 		if !ok {
-			return
+			return true
 		}
 
 		lc, ok := extractLogCall(pass, call)
 		if !ok {
-			return
+			return true
 		}
+		// The stack is reused by the inspector; keep a copy for rules that
+		// look at the enclosing function.
+		lc.stack = append([]ast.Node(nil), stack...)
 
-		analyseCall(pass, cfg, lc)
+		analyseCall(pass, rs, info, lc)
+		return true
 	})
 
 	return nil, nil
@@ -156,12 +192,15 @@ func extractLogCall(pass *analysis.Pass, call *ast.CallExpr) (logCall, bool) {
 	}
 
 	msgArg := call.Args[msgIdx]
-	literal, fullExpr := extractStringValue(pass, msgArg)
+	parts, literal, fullExpr := extractStringValue(pass, msgArg)
 
 	return logCall{
 		pos:        call.Pos(),
+		call:       call,
+		args:       call.Args[msgIdx+1:],
 		msgArg:     msgArg,
 		msgLiteral: literal,
+		parts:      parts,
 		fullExpr:   fullExpr,
 	}, true
 }
@@ -181,6 +220,11 @@ func isSupportedLogMethod(pass *analysis.Pass, sel *ast.SelectorExpr) bool {
 	}
 
 	pkg := pkgPathOf(obj)
+	if strings.HasPrefix(pkg, "go.uber.org/zap") && receiverTypeName(pass, sel) == "" {
+		// Package-level zap functions such as zap.Error(err) build fields;
+		// only Logger and SugaredLogger methods log.
+		return false
+	}
 	return isSupportedPackage(pkg)
 }
 
@@ -235,72 +279,27 @@ func pkgPathOf(obj types.Object) string {
 // messageArgIndex returns the 0-based index of the message string argument for
 // the given logging call.
 //
-//   - For slog and standard log, the message is always argument 0.
+//   - For slog and standard log, the message is argument 0, except for the
+//     context variants (InfoContext etc.) where it follows the context.
 //   - For zap sugar (Infow, Warnw etc.) and non-sugar (Info(msg, fields...)) the
 //     message is also argument 0.
 //   - For zap's Infof/Warnf etc. the message is argument 0 (format string).
 func messageArgIndex(pass *analysis.Pass, sel *ast.SelectorExpr, args []ast.Expr) int {
-	// All supported methods use argument index 0 for the message.
-	// This may need extending if a future logger family differs.
 	_ = pass
-	_ = sel
 	_ = args
+	if name := sel.Sel.Name; strings.HasSuffix(name, "Context") || strings.HasSuffix(name, "Ctx") {
+		return 1
+	}
 	return 0
 }
 
-// extractStringValue attempts to resolve a string constant from the expression.
-// It also returns a full-expression string for the sensitive check.
-func extractStringValue(pass *analysis.Pass, expr ast.Expr) (literal, fullExpr string) {
+// extractStringValue resolves the constant portion of the message
+// expression: string literals and named constants joined by +. It also
+// returns a full-expression string for the sensitive check.
+func extractStringValue(pass *analysis.Pass, expr ast.Expr) (parts []msgPart, literal, fullExpr string) {
 	fullExpr = exprToString(pass, expr)
-
-	// Traverse the expression tree collecting string literals so we can
-	// reconstruct the concatenated value as accurately as possible.
-	var parts []string
-	collectStringParts(pass, expr, &parts)
-	literal = strings.Join(parts, "")
-
-	return literal, fullExpr
-}
-
-// collectStringParts recursively collects string literal values from an
-// expression, following binary + concatenation chains.
-func collectStringParts(pass *analysis.Pass, expr ast.Expr, parts *[]string) {
-	switch e := expr.(type) {
-	case *ast.BasicLit:
-		if e.Kind == token.STRING {
-			// Unquote the raw string literal.
-			s := strings.Trim(e.Value, "`")
-			s = strings.TrimPrefix(s, "\"")
-			s = strings.TrimSuffix(s, "\"")
-			// Handle simple escape sequences.
-			s = strings.ReplaceAll(s, `\"`, `"`)
-			s = strings.ReplaceAll(s, `\\`, `\`)
-			s = strings.ReplaceAll(s, `\n`, "\n")
-			s = strings.ReplaceAll(s, `\t`, "\t")
-			*parts = append(*parts, s)
-		}
-
-	case *ast.BinaryExpr:
-		if e.Op == token.ADD {
-			collectStringParts(pass, e.X, parts)
-			collectStringParts(pass, e.Y, parts)
-		}
-
-	case *ast.Ident:
-		// If the identifier is a typed constant we can evaluate it.
-		if obj, ok := pass.TypesInfo.Uses[e]; ok {
-			if c, ok := obj.(*types.Const); ok {
-				val := c.Val().String()
-				val = strings.Trim(val, `"`)
-				*parts = append(*parts, val)
-			}
-		}
-		// Otherwise the variable name itself is useful for sensitive-data checks
-		// – we leave it to exprToString to capture the raw identifier text.
-
-	case *ast.ParenExpr:
-		collectStringParts(pass, e.X, parts)
-	}
+	parts = messageParts(pass, expr)
+	return parts, constantText(parts), fullExpr
 }
 
 // exprToString returns a textual representation of the expression node,
@@ -322,17 +321,10 @@ func exprToString(pass *analysis.Pass, expr ast.Expr) string {
 		}
 	}
 
-	// Fallback: walk the AST and concatenate what we can.
-	var sb strings.Builder
-	ast.Inspect(expr, func(n ast.Node) bool {
-		if lit, ok := n.(*ast.BasicLit); ok {
-			sb.WriteString(lit.Value)
-		} else if id, ok := n.(*ast.Ident); ok {
-			sb.WriteString(id.Name)
-		}
-		return true
-	})
-	return sb.String()
+	// Fallback: render the expression from the AST. Operators and selectors
+	// are kept so that adjacent identifiers do not run together, which would
+	// confuse identifier-segment matching in the sensitive rule.
+	return types.ExprString(expr)
 }
 
 // sourceFragment returns the raw source bytes for the given position range.
@@ -367,116 +359,174 @@ func sourceFragment(pass *analysis.Pass, filename string, from, to token.Pos) st
 
 // analyseCall runs all enabled rules against the extracted log call and
 // reports diagnostics via pass.Report.
-func analyseCall(pass *analysis.Pass, cfg *config.Config, lc logCall) {
+func analyseCall(pass *analysis.Pass, rs *ruleSet, info *pkgInfo, lc logCall) {
 	msg := lc.msgLiteral
+	// The rules that rewrite the message share a single fix.
+	fix := newMessageFix(pass, rs, info, lc)
 
 	// Rule 1: lowercase first letter.
-	if cfg.IsRuleEnabled(config.RuleLowercase) {
+	if rs.lowercase {
 		if diag := rules.CheckLowercase(msg); diag != "" {
 			d := analysis.Diagnostic{
-				Pos:     lc.msgArg.Pos(),
-				End:     lc.msgArg.End(),
 				Message: diag,
 				// SuggestedFix: auto-lowercase the first letter.
-				SuggestedFixes: suggestLowercaseFix(pass, lc, msg),
+				SuggestedFixes: fix.suggest(config.RuleLowercase),
 			}
+			start := len(msg) - len(strings.TrimLeftFunc(msg, unicode.IsSpace))
+			_, size := utf8.DecodeRuneInString(msg[start:])
+			pos, end := lc.textRange(start, start+size)
+			lc.pointAt(&d, pos, end)
 			reportDiagnostic(pass, d)
 		}
 	}
 
 	// Rule 2: English-only characters.
-	if cfg.IsRuleEnabled(config.RuleEnglish) {
-		if diag := rules.CheckEnglish(msg); diag != "" {
-			d := analysis.Diagnostic{
-				Pos:     lc.msgArg.Pos(),
-				End:     lc.msgArg.End(),
-				Message: diag,
-			}
-			reportDiagnostic(pass, d)
-		}
+	if rs.english != nil {
+		checkEnglish(pass, rs, lc, fix)
 	}
 
 	// Rule 3: No special characters or emoji.
-	if cfg.IsRuleEnabled(config.RuleSpecial) {
-		if diag := rules.CheckSpecialChars(msg, cfg.AllowedSpecialChars); diag != "" {
+	if rs.special != nil {
+		if f := rs.special.Find(msg); f.Message != "" {
 			d := analysis.Diagnostic{
-				Pos:            lc.msgArg.Pos(),
-				End:            lc.msgArg.End(),
-				Message:        diag,
-				SuggestedFixes: suggestSpecialFix(pass, lc, msg, cfg.AllowedSpecialChars),
+				Message:        f.Message,
+				SuggestedFixes: fix.suggest(config.RuleSpecial),
 			}
+			pos, end := lc.textRange(f.Start, f.End)
+			lc.pointAt(&d, pos, end)
 			reportDiagnostic(pass, d)
 		}
 	}
 
 	// Rule 4: No sensitive data.
-	if cfg.IsRuleEnabled(config.RuleSensitive) {
-		if diag := rules.CheckSensitive(msg, lc.fullExpr, cfg.SensitiveKeywords); diag != "" {
-			d := analysis.Diagnostic{
-				Pos:     lc.msgArg.Pos(),
-				End:     lc.msgArg.End(),
-				Message: diag,
-			}
-			reportDiagnostic(pass, d)
-		}
+	if rs.sensitive != nil {
+		checkSensitive(pass, rs, info, lc, fix)
 	}
-}
 
-// ---------------------------------------------------------------------------
-// SuggestedFixes (bonus: auto-correction)
-// ---------------------------------------------------------------------------
+	// Rule 5: No hard-coded personal data in the message or constant args.
+	if rs.pii != nil {
+		checkConstData(pass, lc, rs.pii.Find)
+	}
 
-// suggestLowercaseFix returns a SuggestedFix that lowercases the first rune of
-// the string literal if the message argument is a simple string literal.
-func suggestLowercaseFix(pass *analysis.Pass, lc logCall, msg string) []analysis.SuggestedFix {
-	if msg == "" {
-		return nil
+	// Rule 6: No hard-coded credentials in the message or constant args.
+	if rs.secrets != nil {
+		checkConstData(pass, lc, rs.secrets.Find)
+	}
+
+	// Rule 7 (opt-in): No net/http or net/url values that may embed URLs.
+	if rs.httpValues {
+		checkHTTPValues(pass, info, lc)
 	}
 
-	// Only generate an auto-fix for simple string literals – we don't want to
-	// rewrite complex concatenation expressions automatically.
-	lit, ok := lc.msgArg.(*ast.BasicLit)
-	if !ok || lit.Kind != token.STRING {
-		return nil
+	// Rule 8 (opt-in): Use the *Context slog methods when a ctx is in scope.
+	if rs.context {
+		checkContext(pass, lc)
 	}
 
-	// Determine the fixed string value.
-	runes := []rune(msg)
-	if len(runes) == 0 {
-		return nil
+	// Rule 9: No Fatal/Panic levels outside package main and init.
+	if rs.fatal {
+		checkFatal(pass, rs, lc)
 	}
 
-	fixed := string(append([]rune{lowerRune(runes[0])}, runes[1:]...))
-	if fixed == msg {
-		return nil // already lowercase, no fix needed
+	// Rule 10 (opt-in): Error/Warn calls while handling an error include it.
+	if rs.errorAttr {
+		checkErrorAttr(pass, lc)
 	}
 
-	// Reconstruct the quoted literal.
-	quote := string(lit.Value[0]) // " or `
-	fixedLit := quote + fixed + quote
+	// Rule 11 (opt-in): Do not log an error and return it unwrapped.
+	if rs.logReturn {
+		checkLogReturn(pass, rs, lc)
+	}
 
-	return []analysis.SuggestedFix{
-		{
-			Message: "lowercase first letter of log message",
-			TextEdits: []analysis.TextEdit{
-				{
-					Pos:     lit.Pos(),
-					End:     lit.End(),
-					NewText: []byte(fixedLit),
-				},
-			},
-		},
+	// Rule 12 (opt-in): Message formatting – periods, whitespace, controls.
+	if rs.format != nil {
+		checkFormat(pass, rs.format, lc, fix)
+	}
+
+	// Rule 13 (opt-in): Message length bounds.
+	if rs.length != nil {
+		if diag := rs.length.Check(msg, hasDynamicPart(lc.parts)); diag != "" {
+			reportDiagnostic(pass, analysis.Diagnostic{
+				Pos:     lc.msgArg.Pos(),
+				End:     lc.msgArg.End(),
+				Message: diag,
+			})
+		}
+	}
+
+	// Rule 14 (opt-in): Constant messages for structured loggers.
+	if rs.static {
+		checkStatic(pass, lc, fix)
+	}
+
+	// Rules 15 and 16, duplicate and eventid, run in the cross-package
+	// analyzer, see NewCrossPackageAnalyzer.
+
+	// Rule 17 (opt-in): Spelling of the message words.
+	if rs.spelling != nil {
+		checkSpelling(pass, rs.spelling, lc, fix)
 	}
 }
 
-// lowerRune returns the lowercase version of r.
-func lowerRune(r rune) rune {
-	if r >= 'A' && r <= 'Z' {
-		return r + ('a' - 'A')
+// checkConstData runs a rule that looks for hard-coded data, such as pii or
+// secrets, over the message and the constant string arguments. Findings in
+// the message or in argument literals point at the offending text.
+func checkConstData(pass *analysis.Pass, lc logCall, find func(string) rules.Finding) {
+	if f := find(lc.msgLiteral); f.Message != "" {
+		d := analysis.Diagnostic{Message: f.Message}
+		pos, end := lc.textRange(f.Start, f.End)
+		lc.pointAt(&d, pos, end)
+		reportDiagnostic(pass, d)
+	}
+	for _, c := range constStringArgs(pass, lc.args) {
+		if f := find(c.value); f.Message != "" {
+			part := msgPart{expr: c.expr, value: c.value, constant: true}
+			if lit, ok := c.expr.(*ast.BasicLit); ok {
+				part.lit = lit
+			}
+			pos, end := partRange(part, f.Start, f.End)
+			reportDiagnostic(pass, analysis.Diagnostic{Pos: pos, End: end, Message: f.Message})
+		}
 	}
-	return r
 }
 
+// constString is a constant string expression found among the arguments of a
+// log call, together with its value.
+type constString struct {
+	expr  ast.Expr
+	value string
+}
+
+// constStringArgs returns the constant string expressions among args,
+// including those nested in attribute constructors such as
+// slog.String("email", "a@b.c") or zap.String(...).
+func constStringArgs(pass *analysis.Pass, args []ast.Expr) []constString {
+	var out []constString
+	for _, arg := range args {
+		ast.Inspect(arg, func(n ast.Node) bool {
+			e, ok := n.(ast.Expr)
+			if !ok {
+				return true
+			}
+			tv, ok := pass.TypesInfo.Types[e]
+			if !ok || tv.Value == nil {
+				return true
+			}
+			if tv.Value.Kind() == constant.String {
+				out = append(out, constString{expr: e, value: constant.StringVal(tv.Value)})
+			}
+			// Do not descend into constant expressions: "a" + "b" is
+			// reported once as a whole.
+			return false
+		})
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// SuggestedFixes (bonus: auto-correction)
+// ---------------------------------------------------------------------------
+
 // reportDiagnostic prints a concise summary (file, line, column, status) and
 // then forwards the diagnostic to the analysis framework.
 func reportDiagnostic(pass *analysis.Pass, d analysis.Diagnostic) {
@@ -524,9 +574,13 @@ func cleanSpecialMessage(msg, allowedExtra string) string {
 	b.Grow(len(msg))
 
 	for _, r := range msg {
-		// Drop all non-ASCII runes – this covers emoji and other
-		// pictographic symbols in a simple, conservative way.
+		// Drop non-ASCII runes other than letters and digits – this covers
+		// emoji and other pictographic symbols. Letters of other scripts
+		// are left to the english rule, whose fix transliterates them.
 		if r > unicode.MaxASCII {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			}
 			continue
 		}
 
@@ -558,43 +612,3 @@ func cleanSpecialMessage(msg, allowedExtra string) string {
 
 	return b.String()
 }
-
-// suggestSpecialFix constructs a SuggestedFix that removes emoji and
-// disallowed special characters from simple string literals. For complex
-// expressions (concatenations, variables, formatting) we skip auto-fix to
-// avoid surprising rewrites.
-func suggestSpecialFix(
-	pass *analysis.Pass,
-	lc logCall,
-	msg, allowedExtra string,
-) []analysis.SuggestedFix {
-	if msg == "" {
-		return nil
-	}
-
-	lit, ok := lc.msgArg.(*ast.BasicLit)
-	if !ok || lit.Kind != token.STRING {
-		return nil
-	}
-
-	fixed := cleanSpecialMessage(msg, allowedExtra)
-	if fixed == msg {
-		return nil
-	}
-
-	quote := string(lit.Value[0]) // " or `
-	fixedLit := quote + fixed + quote
-
-	return []analysis.SuggestedFix{
-		{
-			Message: "remove emoji and noisy special characters from log message",
-			TextEdits: []analysis.TextEdit{
-				{
-					Pos:     lit.Pos(),
-					End:     lit.End(),
-					NewText: []byte(fixedLit),
-				},
-			},
-		},
-	}
-}
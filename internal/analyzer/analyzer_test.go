@@ -1,12 +1,17 @@
 package analyzer_test
 
 import (
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 
 	"github.com/Wladim1r/loglinter/internal/analyzer"
+	"github.com/Wladim1r/loglinter/internal/catalog"
 	"github.com/Wladim1r/loglinter/internal/config"
 )
 
@@ -21,6 +26,16 @@ func testdataDir(t *testing.T) string {
 	return filepath.Clean(analysistest.TestData())
 }
 
+// onlyRule returns a default configuration with every rule except name
+// disabled, so that fixture diagnostics are unambiguous.
+func onlyRule(name string) *config.Config {
+	cfg := config.DefaultConfig()
+	for _, rule := range config.RuleNames() {
+		cfg.Rules[rule] = rule == name
+	}
+	return cfg
+}
+
 // TestAnalyzer_Lowercase runs the linter against testdata/src/lowercase and
 // verifies that only the expected diagnostics are reported.
 func TestAnalyzer_Lowercase(t *testing.T) {
@@ -35,6 +50,15 @@ func TestAnalyzer_Lowercase(t *testing.T) {
 	analysistest.Run(t, testdataDir(t), a, "lowercase")
 }
 
+// TestAnalyzer_ZapFields runs the default rules against
+// testdata/src/zapfields: field constructors such as zap.String must not be
+// checked as log calls.
+func TestAnalyzer_ZapFields(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(config.DefaultConfig())
+	analysistest.Run(t, testdataDir(t), a, "zapfields")
+}
+
 // TestAnalyzer_English runs against testdata/src/language.
 func TestAnalyzer_English(t *testing.T) {
 	t.Parallel()
@@ -47,6 +71,14 @@ func TestAnalyzer_English(t *testing.T) {
 	analysistest.Run(t, testdataDir(t), a, "language")
 }
 
+// TestAnalyzer_EnglishFix runs against testdata/src/english and checks the
+// transliterated messages against english.go.golden.
+func TestAnalyzer_EnglishFix(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleEnglish))
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "english")
+}
+
 // TestAnalyzer_Special runs against testdata/src/special.
 func TestAnalyzer_Special(t *testing.T) {
 	t.Parallel()
@@ -78,3 +110,362 @@ func TestAnalyzer_AllRules(t *testing.T) {
 	a := analyzer.NewAnalyzer(config.DefaultConfig())
 	analysistest.Run(t, testdataDir(t), a, "basic")
 }
+
+// TestAnalyzer_DiscoveredConfig verifies that the default analyzer picks up the
+// .loglinter.yaml stored next to the package under analysis.
+func TestAnalyzer_DiscoveredConfig(t *testing.T) {
+	t.Parallel()
+	analysistest.Run(t, testdataDir(t), analyzer.NewFlagConfiguredAnalyzer(nil), "discover")
+}
+
+// TestAnalyzer_PII runs against testdata/src/pii.
+func TestAnalyzer_PII(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RulePII))
+	analysistest.Run(t, testdataDir(t), a, "pii")
+}
+
+// TestAnalyzer_Secrets runs against testdata/src/secrets.
+func TestAnalyzer_Secrets(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleSecrets))
+	analysistest.Run(t, testdataDir(t), a, "secrets")
+}
+
+// TestAnalyzer_HTTPValues runs against testdata/src/httpvalues.
+func TestAnalyzer_HTTPValues(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleHTTPValues))
+	analysistest.Run(t, testdataDir(t), a, "httpvalues")
+}
+
+// TestAnalyzer_Context runs against testdata/src/ctxscope and checks the
+// rewrite to the *Context methods against ctxscope.go.golden.
+func TestAnalyzer_Context(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleContext))
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "ctxscope")
+}
+
+// TestAnalyzer_Fatal runs against testdata/src/fatallib and fatalmain.
+func TestAnalyzer_Fatal(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleFatal))
+	analysistest.Run(t, testdataDir(t), a, "fatallib", "fatalmain")
+}
+
+// TestAnalyzer_FatalAllowedPackages runs against testdata/src/fatalallowed,
+// whose .loglinter.yaml allows Fatal and Panic levels in that package.
+func TestAnalyzer_FatalAllowedPackages(t *testing.T) {
+	t.Parallel()
+	analysistest.Run(t, testdataDir(t), analyzer.NewFlagConfiguredAnalyzer(nil), "fatalallowed")
+}
+
+// TestAnalyzer_ErrorAttr runs against testdata/src/errorattr and checks the
+// appended attributes against errorattr.go.golden.
+func TestAnalyzer_ErrorAttr(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleErrorAttr))
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "errorattr")
+}
+
+// TestAnalyzer_LogReturn runs against testdata/src/logreturn and
+// logreturnboundary, the latter being configured as a logging boundary.
+func TestAnalyzer_LogReturn(t *testing.T) {
+	t.Parallel()
+	cfg := onlyRule(config.RuleLogReturn)
+	cfg.LogReturnAllowedPackages = []string{"logreturnboundary"}
+	a := analyzer.NewAnalyzer(cfg)
+	analysistest.Run(t, testdataDir(t), a, "logreturn", "logreturnboundary")
+}
+
+// TestAnalyzer_Format runs against testdata/src/format and checks the
+// rewritten literals against format.go.golden.
+func TestAnalyzer_Format(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleFormat))
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "format")
+}
+
+// TestAnalyzer_Length runs against testdata/src/length.
+func TestAnalyzer_Length(t *testing.T) {
+	t.Parallel()
+	cfg := onlyRule(config.RuleLength)
+	cfg.Length.MaxLength = 40
+	a := analyzer.NewAnalyzer(cfg)
+	analysistest.Run(t, testdataDir(t), a, "length")
+}
+
+// TestAnalyzer_Static runs against testdata/src/static and checks the
+// suggested fixes against static.go.golden.
+func TestAnalyzer_Static(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleStatic))
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "static")
+}
+
+// TestAnalyzer_Duplicate runs against testdata/src/dupmain, which shares
+// messages with its import dupbase. dupbase alone has no duplicates, and
+// dupsibling, which imports neither, is not compared with them.
+func TestAnalyzer_Duplicate(t *testing.T) {
+	t.Parallel()
+	cfg := onlyRule(config.RuleDuplicate)
+	cfg.Duplicate.AllowedMessages = []string{"operation completed"}
+	a := analyzer.NewCrossPackageAnalyzer(cfg)
+	analysistest.Run(t, testdataDir(t), a, "dupmain", "dupbase", "dupsibling")
+}
+
+// TestAnalyzer_NoFacts guards the cost of a default run: an analyzer with
+// facts anywhere in its dependency graph makes drivers analyze every
+// dependency from source, so facts belong to the cross-package analyzer only.
+func TestAnalyzer_NoFacts(t *testing.T) {
+	t.Parallel()
+	cfg := config.DefaultConfig()
+	cfg.Rules[config.RuleDuplicate] = true
+	cfg.Rules[config.RuleEventID] = true
+	var walk func(a *analysis.Analyzer)
+	walk = func(a *analysis.Analyzer) {
+		if len(a.FactTypes) > 0 {
+			t.Errorf("analyzer %s has fact types %v", a.Name, a.FactTypes)
+		}
+		for _, req := range a.Requires {
+			walk(req)
+		}
+	}
+	walk(analyzer.NewAnalyzer(cfg))
+	walk(analyzer.Analyzer)
+}
+
+// TestCrossPackageRulesEnabled checks the decision to run the cross-package
+// analyzer for an explicit config file and for discovered ones, including
+// those in subdirectories.
+func TestCrossPackageRulesEnabled(t *testing.T) {
+	t.Parallel()
+	write := func(dir, name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	dir := t.TempDir()
+	write(dir, "go.mod", "module example.com/app\n")
+	if analyzer.CrossPackageRulesEnabled("", dir) {
+		t.Error("enabled without a config file, want disabled by default")
+	}
+	write(dir, ".loglinter.yaml", "rules:\n  eventid: true\n")
+	if !analyzer.CrossPackageRulesEnabled("", dir) {
+		t.Error("disabled with eventid enabled in the discovered config")
+	}
+	explicit := write(dir, "other.yaml", "rules:\n  duplicate: false\n")
+	if analyzer.CrossPackageRulesEnabled(explicit, dir) {
+		t.Error("enabled with an explicit config that enables neither rule")
+	}
+
+	nested := t.TempDir()
+	write(nested, "go.mod", "module example.com/app\n")
+	write(nested, "testdata/.loglinter.yaml", "rules:\n  duplicate: true\n")
+	if analyzer.CrossPackageRulesEnabled("", nested) {
+		t.Error("enabled by a config under testdata, want it skipped")
+	}
+	write(nested, "internal/billing/.loglinter.yaml", "rules:\n  duplicate: true\n")
+	if !analyzer.CrossPackageRulesEnabled("", nested) {
+		t.Error("disabled with duplicate enabled only in a subdirectory config")
+	}
+}
+
+// TestCatalogAnalyzer checks the entries produced for testdata/src/catalog.
+func TestCatalogAnalyzer(t *testing.T) {
+	t.Parallel()
+	results := analysistest.Run(t, testdataDir(t), analyzer.CatalogAnalyzer, "catalog")
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	entries := results[0].Result.([]catalog.Entry)
+
+	type summary struct {
+		Message, Level, Logger, Function string
+		Keys                             []string
+		Line                             int
+	}
+	want := []summary{
+		{"request handled", "info", "slog", "server.handle", []string{"id", "n"}, 14},
+		{"retry {n} of {id}", "warn", "slog", "server.handle", nil, 15},
+		{"query failed", "error", "zap", "server.handle", []string{"id", "error"}, 16},
+		{"user ", "info", "zap-sugar", "server.handle", nil, 17},
+		{"cache size %d", "info", "log", "server.handle", nil, 18},
+		{"server started", "debug", "slog", "start", nil, 22},
+	}
+	got := make([]summary, len(entries))
+	for i, e := range entries {
+		got[i] = summary{e.Message, e.Level, e.Logger, e.Function, e.Keys, e.Line}
+		if e.Package != "catalog" || filepath.Base(e.File) != "catalog.go" {
+			t.Errorf("entry %d at %s in %s, want catalog.go in catalog", i, e.File, e.Package)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// TestAnalyzer_EventID runs against testdata/src/eventid, whose import
+// eventidbase already uses some IDs, and checks the generated IDs against
+// eventid.go.golden.
+func TestAnalyzer_EventID(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewCrossPackageAnalyzer(onlyRule(config.RuleEventID))
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "eventid")
+}
+
+// TestAnalyzer_Spelling runs against testdata/src/spelling with a project
+// word list and checks the suggested fixes against spelling.go.golden.
+func TestAnalyzer_Spelling(t *testing.T) {
+	t.Parallel()
+	cfg := onlyRule(config.RuleSpelling)
+	cfg.SpellingWords = []string{"acmepay"}
+	a := analyzer.NewAnalyzer(cfg)
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "spelling")
+}
+
+// TestAnalyzer_Ranges runs the default rules against testdata/src/ranges and
+// checks the source text each diagnostic points at, across escapes, raw
+// strings, concatenated parts and named constants.
+func TestAnalyzer_Ranges(t *testing.T) {
+	t.Parallel()
+	results := analysistest.Run(t, testdataDir(t), analyzer.NewAnalyzer(config.DefaultConfig()), "ranges")
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	pass := results[0].Pass
+
+	// want maps the line of each diagnostic to the text it points at.
+	want := map[int]string{
+		11: "S",
+		12: "!",
+		13: `\"`,
+		14: "#",
+		15: `\u043e`,
+		16: "δ",
+		17: "password",
+		18: "token",
+		19: "a@example.com",
+		20: "198.51.100.3",
+		21: "ready",
+	}
+	for _, d := range results[0].Diagnostics {
+		start, end := pass.Fset.Position(d.Pos), pass.Fset.Position(d.End)
+		src, err := os.ReadFile(start.Filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(src[start.Offset:end.Offset]); got != want[start.Line] {
+			t.Errorf("line %d: diagnostic points at %q, want %q", start.Line, got, want[start.Line])
+		}
+		if len(d.Related) == 0 && start.Line != 19 {
+			t.Errorf("line %d: no related information for the whole message", start.Line)
+		}
+	}
+}
+
+// TestAnalyzer_Fixes runs every fixable rule against testdata/src/fixes and
+// checks that the diagnostics of each call share one fix, applied to every
+// literal of the message, against fixes.go.golden.
+func TestAnalyzer_Fixes(t *testing.T) {
+	t.Parallel()
+	cfg := config.DefaultConfig()
+	cfg.Rules[config.RuleFormat] = true
+	cfg.Rules[config.RuleSpelling] = true
+	a := analyzer.NewAnalyzer(cfg)
+	results := analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "fixes")
+
+	// Diagnostics the fix would not remove carry no fix.
+	noFix := map[int]string{19: "lowercase letter", 20: "non-English characters"}
+	fixes := make(map[int][]analysis.SuggestedFix)
+	for _, d := range results[0].Diagnostics {
+		line := results[0].Pass.Fset.Position(d.Pos).Line
+		if want, ok := noFix[line]; ok && strings.Contains(d.Message, want) {
+			if len(d.SuggestedFixes) > 0 {
+				t.Errorf("line %d: %q offers a fix that leaves it in place", line, d.Message)
+			}
+			continue
+		}
+		if len(d.SuggestedFixes) == 0 {
+			t.Errorf("line %d: %q offers no fix", line, d.Message)
+			continue
+		}
+		if prev, ok := fixes[line]; ok && !reflect.DeepEqual(prev, d.SuggestedFixes) {
+			t.Errorf("line %d: diagnostics offer different fixes:\n%+v\n%+v", line, prev, d.SuggestedFixes)
+		}
+		fixes[line] = d.SuggestedFixes
+	}
+}
+
+// TestAnalyzer_RedactDrop runs against testdata/src/redactdrop and checks
+// that sensitive attributes and operands are dropped without a helper.
+func TestAnalyzer_RedactDrop(t *testing.T) {
+	t.Parallel()
+	a := analyzer.NewAnalyzer(onlyRule(config.RuleSensitive))
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "redactdrop")
+}
+
+// TestAnalyzer_RedactFixes runs the default rules against
+// testdata/src/redactfixes and, with a redaction helper, redactfixeswrap,
+// and checks that the sensitive rule and the rules rewriting the text of the
+// message offer one fix per call.
+func TestAnalyzer_RedactFixes(t *testing.T) {
+	t.Parallel()
+	wrap := config.DefaultConfig()
+	wrap.Redaction = config.RedactionConfig{Package: "acme/redact", Function: "String"}
+	for pkg, cfg := range map[string]*config.Config{
+		"redactfixes":     config.DefaultConfig(),
+		"redactfixeswrap": wrap,
+	} {
+		results := analysistest.RunWithSuggestedFixes(t, testdataDir(t), analyzer.NewAnalyzer(cfg), pkg)
+		checkSharedFixes(t, results[0])
+	}
+}
+
+// TestAnalyzer_StaticFixes runs the default rules with format and static
+// against testdata/src/staticfixes and checks that the static rewrite is
+// part of the fix of the message, against staticfixes.go.golden.
+func TestAnalyzer_StaticFixes(t *testing.T) {
+	t.Parallel()
+	cfg := config.DefaultConfig()
+	cfg.Rules[config.RuleFormat] = true
+	cfg.Rules[config.RuleStatic] = true
+	results := analysistest.RunWithSuggestedFixes(t, testdataDir(t), analyzer.NewAnalyzer(cfg), "staticfixes")
+	checkSharedFixes(t, results[0])
+}
+
+// checkSharedFixes checks that every diagnostic offers a fix and that the
+// diagnostics of a line offer the same one.
+func checkSharedFixes(t *testing.T, result *analysistest.Result) {
+	t.Helper()
+	fixes := make(map[int][]analysis.SuggestedFix)
+	for _, d := range result.Diagnostics {
+		pos := result.Pass.Fset.Position(d.Pos)
+		if len(d.SuggestedFixes) == 0 {
+			t.Errorf("%s:%d: %q offers no fix", pos.Filename, pos.Line, d.Message)
+			continue
+		}
+		if prev, ok := fixes[pos.Line]; ok && !reflect.DeepEqual(prev, d.SuggestedFixes) {
+			t.Errorf("%s:%d: diagnostics offer different fixes:\n%+v\n%+v", pos.Filename, pos.Line, prev, d.SuggestedFixes)
+		}
+		fixes[pos.Line] = d.SuggestedFixes
+	}
+}
+
+// TestAnalyzer_RedactWrap runs against testdata/src/redactwrap and checks
+// that sensitive values are wrapped in the configured helper, importing it
+// under a free name where needed.
+func TestAnalyzer_RedactWrap(t *testing.T) {
+	t.Parallel()
+	cfg := onlyRule(config.RuleSensitive)
+	cfg.Redaction = config.RedactionConfig{Package: "acme/redact", Function: "String"}
+	a := analyzer.NewAnalyzer(cfg)
+	analysistest.RunWithSuggestedFixes(t, testdataDir(t), a, "redactwrap")
+}
@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// loggerFamily names the logger of a supported log method: slog, zap,
+// zap-sugar or log.
+func loggerFamily(pass *analysis.Pass, sel *ast.SelectorExpr) string {
+	switch pkg := pkgPathOf(pass.TypesInfo.Uses[sel.Sel]); {
+	case pkg == "log/slog":
+		return "slog"
+	case pkg == "log":
+		return "log"
+	case receiverTypeName(pass, sel) == "go.uber.org/zap.SugaredLogger":
+		return "zap-sugar"
+	}
+	return "zap"
+}
+
+// logAttr is an attribute with a constant key passed to a structured log
+// call. value is nil when the attribute has no single value expression, as
+// for slog.Group. args are the call arguments the attribute spans: a slog
+// key and its value, or a single constructor call.
+type logAttr struct {
+	key   string
+	value ast.Expr
+	args  []ast.Expr
+}
+
+// logAttrs returns the attributes with constant keys among the arguments
+// that follow the message: slog key/value pairs and attribute constructors
+// such as slog.String("k", v) or zap.String("k", v). zap.Error(err) has the
+// key "error". The unstructured loggers have no attributes.
+func logAttrs(pass *analysis.Pass, family string, args []ast.Expr) []logAttr {
+	if family != "slog" && family != "zap" {
+		return nil
+	}
+	var attrs []logAttr
+	for i := 0; i < len(args); i++ {
+		if a, ok := attrConstructor(pass, args[i]); ok {
+			a.args = args[i : i+1]
+			attrs = append(attrs, a)
+			continue
+		}
+		if family != "slog" {
+			continue
+		}
+		// A slog key is followed by its value.
+		if tv, ok := pass.TypesInfo.Types[args[i]]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			a := logAttr{key: constant.StringVal(tv.Value), args: args[i:min(i+2, len(args))]}
+			if i+1 < len(args) {
+				a.value = args[i+1]
+			}
+			attrs = append(attrs, a)
+			i++
+		}
+	}
+	return attrs
+}
+
+// attrKeys returns the keys of logAttrs.
+func attrKeys(pass *analysis.Pass, family string, args []ast.Expr) []string {
+	var keys []string
+	for _, a := range logAttrs(pass, family, args) {
+		keys = append(keys, a.key)
+	}
+	return keys
+}
+
+// attrConstructor recognises a call to a slog or zap attribute constructor
+// whose first argument is a constant key.
+func attrConstructor(pass *analysis.Pass, e ast.Expr) (logAttr, bool) {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return logAttr{}, false
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return logAttr{}, false
+	}
+	switch fn.Pkg().Path() {
+	case "log/slog", "go.uber.org/zap":
+	default:
+		return logAttr{}, false
+	}
+	if len(call.Args) == 0 {
+		return logAttr{}, false
+	}
+	if fn.Pkg().Path() == "go.uber.org/zap" && fn.Name() == "Error" {
+		return logAttr{key: "error", value: call.Args[0]}, true
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return logAttr{}, false
+	}
+	a := logAttr{key: constant.StringVal(tv.Value)}
+	if len(call.Args) == 2 {
+		a.value = call.Args[1]
+	}
+	return a, true
+}
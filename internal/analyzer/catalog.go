@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/Wladim1r/loglinter/internal/catalog"
+)
+
+// CatalogAnalyzer describes every supported log call of a package as a
+// catalog.Entry. It reports nothing; its result is the package's entries in
+// source order, with absolute file names. It backs the catalog subcommand.
+var CatalogAnalyzer = &analysis.Analyzer{
+	Name:       "loglintercatalog",
+	Doc:        "lists the log calls of a package with their message, level, logger and attribute keys",
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	ResultType: reflect.TypeOf([]catalog.Entry(nil)),
+	Run:        runCatalog,
+}
+
+func runCatalog(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	var entries []catalog.Entry
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		lc, ok := extractLogCall(pass, n.(*ast.CallExpr))
+		if !ok {
+			return true
+		}
+		sel := lc.call.Fun.(*ast.SelectorExpr)
+		family := loggerFamily(pass, sel)
+		pos := pass.Fset.Position(lc.call.Pos())
+		entries = append(entries, catalog.Entry{
+			Message:  messageTemplate(pass, lc.parts),
+			Dynamic:  hasDynamicPart(lc.parts),
+			Level:    methodLevel(sel.Sel.Name).String(),
+			Logger:   family,
+			Keys:     attrKeys(pass, family, lc.args),
+			Package:  pass.Pkg.Path(),
+			Function: funcName(pass, stack),
+			File:     pos.Filename,
+			Line:     pos.Line,
+		})
+		return true
+	})
+	return entries, nil
+}
+
+// messageTemplate joins the parts of a message, writing the operands that
+// are not constant as {expr}. A fmt.Sprintf call with a constant format is
+// expanded the same way: "retry {n} of {url}".
+func messageTemplate(pass *analysis.Pass, parts []msgPart) string {
+	var b strings.Builder
+	for _, p := range parts {
+		if p.constant {
+			b.WriteString(p.value)
+			continue
+		}
+		fragments, values, ok := sprintfOperands(pass, p.expr)
+		if !ok {
+			b.WriteString("{" + types.ExprString(p.expr) + "}")
+			continue
+		}
+		for i, v := range values {
+			b.WriteString(fragments[i] + "{" + types.ExprString(v) + "}")
+		}
+		b.WriteString(fragments[len(fragments)-1])
+	}
+	return b.String()
+}
+
+// funcName names the function declaration enclosing the top of stack, as
+// Func or Type.Method, or returns "" outside functions.
+func funcName(pass *analysis.Pass, stack []ast.Node) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		fd, ok := stack[i].(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		fn, ok := pass.TypesInfo.Defs[fd.Name].(*types.Func)
+		if !ok {
+			return fd.Name.Name
+		}
+		recv := fn.Type().(*types.Signature).Recv()
+		if recv == nil {
+			return fn.Name()
+		}
+		t := recv.Type()
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem()
+		}
+		if named, ok := types.Unalias(t).(*types.Named); ok {
+			return named.Obj().Name() + "." + fn.Name()
+		}
+		return fn.Name()
+	}
+	return ""
+}
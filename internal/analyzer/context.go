@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkContext reports slog calls without a context (Info, Warn, Error,
+// Debug) made where a context.Context or *http.Request parameter of an
+// enclosing function is in scope, and suggests the matching *Context method.
+// Handlers that use the context variants get trace and request IDs attached
+// by context-aware slog handlers.
+func checkContext(pass *analysis.Pass, lc logCall) {
+	sel, ok := lc.call.Fun.(*ast.SelectorExpr)
+	if !ok || !isPlainSlogMethod(pass, sel) {
+		return
+	}
+	ctx, ok := contextInScope(pass, lc.stack)
+	if !ok {
+		return
+	}
+
+	method := sel.Sel.Name + "Context"
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: sel.Pos(),
+		End: sel.End(),
+		Message: fmt.Sprintf("%s called while %s is in scope; use %s(%s, ...) so the context reaches the handler",
+			types.ExprString(sel), ctx, method, ctx),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: fmt.Sprintf("Use %s with %s", method, ctx),
+			TextEdits: []analysis.TextEdit{
+				{Pos: sel.Sel.Pos(), End: sel.Sel.End(), NewText: []byte(method)},
+				{Pos: lc.call.Args[0].Pos(), End: lc.call.Args[0].Pos(), NewText: []byte(ctx + ", ")},
+			},
+		}},
+	})
+}
+
+// isPlainSlogMethod reports whether sel is one of the slog functions or
+// *slog.Logger methods that have a *Context counterpart.
+func isPlainSlogMethod(pass *analysis.Pass, sel *ast.SelectorExpr) bool {
+	if pkgPathOf(pass.TypesInfo.Uses[sel.Sel]) != "log/slog" {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "Info", "Warn", "Error", "Debug":
+		return true
+	}
+	return false
+}
+
+// contextInScope returns an expression for the context available to a call
+// with the given enclosing stack: a context.Context parameter of the
+// innermost enclosing function that has one, or r.Context() for an
+// *http.Request parameter r. Unnamed and blank parameters are ignored.
+func contextInScope(pass *analysis.Pass, stack []ast.Node) (string, bool) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		var ft *ast.FuncType
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			ft = fn.Type
+		case *ast.FuncLit:
+			ft = fn.Type
+		default:
+			continue
+		}
+
+		var fromRequest string
+		for _, field := range ft.Params.List {
+			t := pass.TypesInfo.TypeOf(field.Type)
+			for _, name := range field.Names {
+				if name.Name == "_" {
+					continue
+				}
+				switch {
+				case isNamedType(t, "context", "Context"):
+					return name.Name, true
+				case fromRequest == "" && isPointerTo(t, "net/http", "Request"):
+					fromRequest = name.Name + ".Context()"
+				}
+			}
+		}
+		if fromRequest != "" {
+			return fromRequest, true
+		}
+	}
+	return "", false
+}
+
+// isNamedType reports whether t is the named type pkg.name.
+func isNamedType(t types.Type, pkg, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkg && named.Obj().Name() == name
+}
+
+// isPointerTo reports whether t is *pkg.name.
+func isPointerTo(t types.Type, pkg, name string) bool {
+	p, ok := t.(*types.Pointer)
+	return ok && isNamedType(p.Elem(), pkg, name)
+}
@@ -0,0 +1,26 @@
+package analyzer
+
+import (
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkDuplicate reports a constant message that the package and its
+// imports use at more call sites than allowed. Every package reports its own
+// call sites; the diagnostic lists all of them.
+//
+// Call sites are only compared along imports: an imported package does not
+// see the packages that import it, so only the importer reports a message
+// they share, and packages that do not import each other are never
+// compared.
+func checkDuplicate(pass *analysis.Pass, rs *ruleSet, index *logIndex, lc logCall) {
+	if lc.msgLiteral == "" || hasDynamicPart(lc.parts) {
+		return
+	}
+	if diag := rs.duplicate.Check(lc.msgLiteral, index.messages[lc.msgLiteral]); diag != "" {
+		reportDiagnostic(pass, analysis.Diagnostic{
+			Pos:     lc.msgArg.Pos(),
+			End:     lc.msgArg.End(),
+			Message: diag,
+		})
+	}
+}
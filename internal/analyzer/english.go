@@ -0,0 +1,28 @@
+package analyzer
+
+import (
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/Wladim1r/loglinter/internal/config"
+)
+
+// checkEnglish runs the english rule over the constant parts of the message
+// and reports the first offending rune, with its column within the part that
+// holds it.
+func checkEnglish(pass *analysis.Pass, rs *ruleSet, lc logCall, fix *messageFix) {
+	for _, part := range lc.parts {
+		if !part.constant {
+			continue
+		}
+		if f := rs.english.Find(part.value); f.Message != "" {
+			d := analysis.Diagnostic{
+				Message:        f.Message,
+				SuggestedFixes: fix.suggest(config.RuleEnglish),
+			}
+			pos, end := partRange(part, f.Start, f.End)
+			lc.pointAt(&d, pos, end)
+			reportDiagnostic(pass, d)
+			return
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// errorIface is the underlying interface of the predeclared error type.
+var errorIface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// checkErrorAttr reports Error- and Warn-level calls made while handling an
+// error – inside the body of `if err != nil` – that do not reference err in
+// any argument. Any use counts: "err", err, slog.Any("err", err),
+// zap.Error(err), an err operand of a format string or err.Error().
+//
+// The suggested fix appends the error in the idiom of the logger: "err", err
+// for slog, zap.Error(err) for *zap.Logger and err for the sugared logger.
+func checkErrorAttr(pass *analysis.Pass, lc logCall) {
+	sel, ok := lc.call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	level := methodLevel(sel.Sel.Name)
+	if level != levelError && level != levelWarn {
+		return
+	}
+	errVar := handledError(pass, lc.stack)
+	if errVar == nil || referencesVar(pass, lc.call.Args, errVar) {
+		return
+	}
+
+	levelName := "Error"
+	if level == levelWarn {
+		levelName = "Warn"
+	}
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos: lc.call.Pos(),
+		End: lc.call.End(),
+		Message: fmt.Sprintf("%s-level log call inside `if %s != nil` does not include %s; attach it to the log entry",
+			levelName, errVar.Name(), errVar.Name()),
+		SuggestedFixes: suggestErrorAttrFix(pass, lc, sel, errVar),
+	})
+}
+
+// handledError returns the error variable checked by the innermost enclosing
+// `if x != nil` whose then-branch contains the call, without crossing a
+// function boundary. Conditions joined with && are searched too, as in
+// `if err != nil && !errors.Is(err, io.EOF)`.
+func handledError(pass *analysis.Pass, stack []ast.Node) *types.Var {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return nil
+		case *ast.IfStmt:
+			if stack[i+1] != n.Body {
+				continue
+			}
+			if v := nonNilError(pass, n.Cond); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// nonNilError returns the error variable x of a condition `x != nil` (or
+// `nil != x`), looking through && operands.
+func nonNilError(pass *analysis.Pass, cond ast.Expr) *types.Var {
+	bin, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok {
+		return nil
+	}
+	switch bin.Op {
+	case token.LAND:
+		if v := nonNilError(pass, bin.X); v != nil {
+			return v
+		}
+		return nonNilError(pass, bin.Y)
+	case token.NEQ:
+		x, y := bin.X, bin.Y
+		if tv, ok := pass.TypesInfo.Types[x]; ok && tv.IsNil() {
+			x, y = y, x
+		}
+		if tv, ok := pass.TypesInfo.Types[y]; !ok || !tv.IsNil() {
+			return nil
+		}
+		id, ok := ast.Unparen(x).(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		if !ok || !types.Implements(v.Type(), errorIface) {
+			return nil
+		}
+		return v
+	}
+	return nil
+}
+
+// referencesVar reports whether any of exprs mentions v.
+func referencesVar(pass *analysis.Pass, exprs []ast.Expr, v *types.Var) bool {
+	found := false
+	for _, e := range exprs {
+		ast.Inspect(e, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(id) == v {
+				found = true
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// suggestErrorAttrFix appends errVar to the arguments of the call. No fix is
+// offered for spread calls (args...) or for *zap.Logger calls in files that
+// do not import zap.
+func suggestErrorAttrFix(pass *analysis.Pass, lc logCall, sel *ast.SelectorExpr, errVar *types.Var) []analysis.SuggestedFix {
+	if lc.call.Ellipsis.IsValid() || len(lc.call.Args) == 0 {
+		return nil
+	}
+	name := errVar.Name()
+
+	var attr string
+	switch recv := receiverTypeName(pass, sel); {
+	case pkgPathOf(pass.TypesInfo.Uses[sel.Sel]) == "log/slog":
+		attr = fmt.Sprintf("%q, %s", "err", name)
+	case recv == "go.uber.org/zap.Logger":
+		zapName := importName(lc.stack, "go.uber.org/zap")
+		if zapName == "" {
+			return nil
+		}
+		attr = fmt.Sprintf("%s.Error(%s)", zapName, name)
+	case recv == "go.uber.org/zap.SugaredLogger":
+		attr = name
+	default:
+		return nil
+	}
+
+	last := lc.call.Args[len(lc.call.Args)-1]
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Attach %s to the log entry", name),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     last.End(),
+			End:     last.End(),
+			NewText: []byte(", " + attr),
+		}},
+	}}
+}
+
+// receiverTypeName returns "pkgpath.Type" for the receiver of the method
+// selected by sel, or "" for functions.
+func receiverTypeName(pass *analysis.Pass, sel *ast.SelectorExpr) string {
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return ""
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return ""
+	}
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
+
+// importName returns the name under which the file at the bottom of stack
+// imports importPath, or "" when it does not import it.
+func importName(stack []ast.Node, importPath string) string {
+	if len(stack) == 0 {
+		return ""
+	}
+	file, ok := stack[0].(*ast.File)
+	if !ok {
+		return ""
+	}
+	for _, imp := range file.Imports {
+		if imp.Path.Value != strconv.Quote(importPath) {
+			continue
+		}
+		if imp.Name != nil {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				return ""
+			}
+			return imp.Name.Name
+		}
+		return path.Base(importPath)
+	}
+	return ""
+}
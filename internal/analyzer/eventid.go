@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"strconv"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkEventID requires slog and *zap.Logger calls of the configured levels
+// to carry the event ID attribute, with a constant value that matches the
+// configured pattern and is not used by any other call site of the package
+// or the packages of the same module it imports. Facts only flow along
+// imports, so packages that do not import each other are not compared.
+//
+// Calls without the attribute get a fix appending a generated ID derived
+// from the package name and the message.
+func checkEventID(pass *analysis.Pass, rs *ruleSet, index *logIndex, newEventIDs map[string]bool, lc logCall) {
+	sel, ok := lc.call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	family := loggerFamily(pass, sel)
+	level := methodLevel(sel.Sel.Name)
+	if (family != "slog" && family != "zap") || !rs.eventIDLevels[level.String()] {
+		return
+	}
+	ev := rs.eventID
+
+	var attr *logAttr
+	for _, a := range logAttrs(pass, family, lc.args) {
+		if a.key == ev.Key() {
+			attr = &a
+			break
+		}
+	}
+	if attr == nil {
+		reportDiagnostic(pass, analysis.Diagnostic{
+			Pos: lc.call.Pos(),
+			End: lc.call.End(),
+			Message: fmt.Sprintf("%s-level log call has no %q attribute; add a stable event ID so alerts survive changes to the message",
+				level, ev.Key()),
+			SuggestedFixes: suggestEventIDFix(pass, rs, index, newEventIDs, lc, family),
+		})
+		return
+	}
+	if attr.value == nil {
+		return
+	}
+
+	tv, ok := pass.TypesInfo.Types[attr.value]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		reportDiagnostic(pass, analysis.Diagnostic{
+			Pos:     attr.value.Pos(),
+			End:     attr.value.End(),
+			Message: fmt.Sprintf("value of %q must be a constant string so the event ID stays stable", ev.Key()),
+		})
+		return
+	}
+	id := constant.StringVal(tv.Value)
+	for _, diag := range []string{
+		ev.CheckValue(id),
+		ev.CheckUnique(id, index.attrs[siteAttr{Key: ev.Key(), Value: id}]),
+	} {
+		if diag != "" {
+			reportDiagnostic(pass, analysis.Diagnostic{
+				Pos:     attr.value.Pos(),
+				End:     attr.value.End(),
+				Message: diag,
+			})
+		}
+	}
+}
+
+// suggestEventIDFix appends the event ID attribute with a new ID that is not
+// used in the package, its imports or earlier fixes of the same pass. No fix
+// is offered for spread calls (args...), for *zap.Logger calls in files that
+// do not import zap, or when the generated ID does not match the pattern.
+func suggestEventIDFix(pass *analysis.Pass, rs *ruleSet, index *logIndex, newEventIDs map[string]bool, lc logCall, family string) []analysis.SuggestedFix {
+	if lc.call.Ellipsis.IsValid() {
+		return nil
+	}
+	ev := rs.eventID
+	id := ev.Generate(pass.Pkg.Name(), lc.msgLiteral, func(id string) bool {
+		return newEventIDs[id] || len(index.attrs[siteAttr{Key: ev.Key(), Value: id}]) > 0
+	})
+	if id == "" {
+		return nil
+	}
+
+	var attr string
+	if family == "slog" {
+		attr = strconv.Quote(ev.Key()) + ", " + strconv.Quote(id)
+	} else {
+		zapName := importName(lc.stack, "go.uber.org/zap")
+		if zapName == "" {
+			return nil
+		}
+		attr = fmt.Sprintf("%s.String(%q, %q)", zapName, ev.Key(), id)
+	}
+	newEventIDs[id] = true
+
+	last := lc.call.Args[len(lc.call.Args)-1]
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("Add %s %q", ev.Key(), id),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     last.End(),
+			End:     last.End(),
+			NewText: []byte(", " + attr),
+		}},
+	}}
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/Wladim1r/loglinter/internal/config"
+)
+
+// NewCrossPackageAnalyzer constructs the analyzer of the rules that look
+// across packages, duplicate and eventid, using the given configuration.
+// Passing nil uses DefaultConfig(), where both rules are off.
+//
+// The rules live in their own analyzer because they exchange package facts:
+// a driver running an analyzer with facts loads and analyzes every
+// dependency from source, the standard library included. Run it next to the
+// main analyzer only when one of the rules is enabled, see
+// CrossPackageRulesEnabled.
+func NewCrossPackageAnalyzer(cfg *config.Config) *analysis.Analyzer {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	rs, err := compileRules(cfg)
+	return newCrossPackageAnalyzer(func(*analysis.Pass) (*ruleSet, error) {
+		return rs, err
+	})
+}
+
+// CrossPackageAnalyzer is the cross-package counterpart of Analyzer: it
+// discovers .loglinter.yaml files for every package.
+var CrossPackageAnalyzer = NewFlagConfiguredCrossPackageAnalyzer(nil)
+
+// NewFlagConfiguredCrossPackageAnalyzer is the cross-package counterpart of
+// NewFlagConfiguredAnalyzer, loading configuration from the path flag or
+// discovering it per package.
+func NewFlagConfiguredCrossPackageAnalyzer(configPath *string) *analysis.Analyzer {
+	cache := newRuleSetCache()
+	return newCrossPackageAnalyzer(func(pass *analysis.Pass) (*ruleSet, error) {
+		if configPath != nil && *configPath != "" {
+			return cache.forFiles([]string{*configPath})
+		}
+		return cache.forDir(packageDir(pass))
+	})
+}
+
+// CrossPackageRulesEnabled reports whether the configuration at configPath,
+// or one discovered for dir or for a package below it when configPath is "",
+// enables duplicate or eventid, so that a driver needs CrossPackageAnalyzer.
+// A configuration that fails to load counts as disabled; the main analyzer
+// reports the error.
+func CrossPackageRulesEnabled(configPath, dir string) bool {
+	if configPath != "" {
+		cfg, err := config.LoadFiles(configPath)
+		return err == nil && crossPackageRulesEnabled(cfg)
+	}
+	if cfg, err := config.Resolve(dir); err == nil && crossPackageRulesEnabled(cfg) {
+		return true
+	}
+	for _, file := range config.DiscoverNested(dir) {
+		if cfg, err := config.Resolve(filepath.Dir(file)); err == nil && crossPackageRulesEnabled(cfg) {
+			return true
+		}
+	}
+	return false
+}
+
+func crossPackageRulesEnabled(cfg *config.Config) bool {
+	return cfg.IsRuleEnabled(config.RuleDuplicate) || cfg.IsRuleEnabled(config.RuleEventID)
+}
+
+// newCrossPackageAnalyzer builds the cross-package analyzer. It collects the
+// constant messages and constant string attributes of the log calls in
+// every package and exports them as a package fact, so that a package sees
+// those of the packages it imports: duplicate counts message call sites and
+// eventid checks that event IDs are unique. Facts are collected whatever the
+// configuration of the package, since importers may enable the rules.
+func newCrossPackageAnalyzer(load func(*analysis.Pass) (*ruleSet, error)) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name:      "loglintercross",
+		Doc:       "checks log messages across packages for duplicate messages and unique event IDs",
+		Requires:  []*analysis.Analyzer{inspect.Analyzer},
+		FactTypes: []analysis.Fact{new(logCallsFact)},
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			index := collectLogCalls(pass)
+			rs, err := load(pass)
+			if err != nil {
+				return nil, err
+			}
+			runCrossPackage(pass, rs, index)
+			return nil, nil
+		},
+	}
+}
+
+// runCrossPackage runs the enabled cross-package rules over the log calls of
+// the package.
+func runCrossPackage(pass *analysis.Pass, rs *ruleSet, index *logIndex) {
+	if rs.duplicate == nil && rs.eventID == nil {
+		return
+	}
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	// newEventIDs are the IDs generated by eventid fixes so far, so that
+	// two calls never get the same ID.
+	newEventIDs := make(map[string]bool)
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		lc, ok := extractLogCall(pass, n.(*ast.CallExpr))
+		if !ok {
+			return true
+		}
+		lc.stack = append([]ast.Node(nil), stack...)
+
+		// Rule 15 (opt-in): Constant messages shared by too many call sites.
+		if rs.duplicate != nil {
+			checkDuplicate(pass, rs, index, lc)
+		}
+		// Rule 16 (opt-in): Stable, unique event IDs on selected levels.
+		if rs.eventID != nil {
+			checkEventID(pass, rs, index, newEventIDs, lc)
+		}
+		return true
+	})
+}
+
+// logCallsFact lists the log calls of a package that have a constant
+// message or constant string attributes.
+type logCallsFact struct {
+	// Module is the path of the package's module, or "" when unknown.
+	// Only facts of the same module are indexed.
+	Module string
+	Sites  []logSite
+}
+
+// logSite is one log call. File is written as "import/path/file.go", which
+// stays meaningful in other packages. Message is "" when the message is not
+// constant.
+type logSite struct {
+	Message string
+	Attrs   []siteAttr
+	File    string
+	Line    int
+}
+
+// siteAttr is an attribute with a constant string value.
+type siteAttr struct {
+	Key, Value string
+}
+
+func (*logCallsFact) AFact() {}
+
+func (f *logCallsFact) String() string {
+	return fmt.Sprintf("%d log calls", len(f.Sites))
+}
+
+// logIndex maps constant messages and attribute values to their call sites
+// in a package and the packages of the same module it imports, as sorted
+// "file:line" strings.
+type logIndex struct {
+	messages map[string][]string
+	attrs    map[siteAttr][]string
+}
+
+// collectLogCalls exports the log calls fact of the package and indexes it
+// together with the facts of its imports.
+func collectLogCalls(pass *analysis.Pass) *logIndex {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	fact := &logCallsFact{}
+	if pass.Module != nil {
+		fact.Module = pass.Module.Path
+	}
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		lc, ok := extractLogCall(pass, n.(*ast.CallExpr))
+		if !ok {
+			return
+		}
+		site := logSite{}
+		if !hasDynamicPart(lc.parts) {
+			site.Message = lc.msgLiteral
+		}
+		sel := lc.call.Fun.(*ast.SelectorExpr)
+		for _, a := range logAttrs(pass, loggerFamily(pass, sel), lc.args) {
+			if a.value == nil {
+				continue
+			}
+			if tv, ok := pass.TypesInfo.Types[a.value]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+				site.Attrs = append(site.Attrs, siteAttr{Key: a.key, Value: constant.StringVal(tv.Value)})
+			}
+		}
+		if site.Message == "" && len(site.Attrs) == 0 {
+			return
+		}
+		pos := pass.Fset.Position(lc.msgArg.Pos())
+		site.File = pass.Pkg.Path() + "/" + path.Base(pos.Filename)
+		site.Line = pos.Line
+		fact.Sites = append(fact.Sites, site)
+	})
+	if len(fact.Sites) > 0 {
+		pass.ExportPackageFact(fact)
+	}
+
+	sites := append([]logSite(nil), fact.Sites...)
+	for _, pf := range pass.AllPackageFacts() {
+		f, ok := pf.Fact.(*logCallsFact)
+		if !ok || pf.Package == pass.Pkg || f.Module != fact.Module {
+			continue
+		}
+		sites = append(sites, f.Sites...)
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].File != sites[j].File {
+			return sites[i].File < sites[j].File
+		}
+		return sites[i].Line < sites[j].Line
+	})
+
+	index := &logIndex{
+		messages: make(map[string][]string),
+		attrs:    make(map[siteAttr][]string),
+	}
+	for _, s := range sites {
+		pos := fmt.Sprintf("%s:%d", s.File, s.Line)
+		if s.Message != "" {
+			index.messages[s.Message] = append(index.messages[s.Message], pos)
+		}
+		for _, a := range s.Attrs {
+			index.attrs[a] = append(index.attrs[a], pos)
+		}
+	}
+	return index
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkFatal reports Fatal- and Panic-level log calls in library code, where
+// they take down the host process. Package main, init functions and the
+// allowed packages may use them. Test files are not checked.
+//
+// Fatal calls inside goroutines or after a defer statement that always runs
+// before them are reported in every package: the process exits without
+// running the deferred cleanup.
+func checkFatal(pass *analysis.Pass, rs *ruleSet, lc logCall) {
+	sel, ok := lc.call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	level := methodLevel(sel.Sel.Name)
+	if level != levelFatal && level != levelPanic {
+		return
+	}
+	if strings.HasSuffix(pass.Fset.File(lc.call.Pos()).Name(), "_test.go") {
+		return
+	}
+	name := types.ExprString(sel)
+
+	var msg string
+	switch {
+	case level == levelFatal && inGoroutine(lc.stack):
+		msg = fmt.Sprintf("%s inside a goroutine exits the whole process without running deferred calls; "+
+			"report the error to the caller instead", name)
+	case level == levelFatal && deferredBefore(lc.stack):
+		msg = fmt.Sprintf("%s after defer exits the process without running the deferred calls; "+
+			"return an error instead", name)
+	case pass.Pkg.Name() == "main" || inInit(lc.stack) || isAllowedPackage(pass.Pkg.Path(), rs.fatalAllowedPackages):
+		return
+	default:
+		msg = fmt.Sprintf("%s in library package %s terminates the host process; return an error instead",
+			name, pass.Pkg.Name())
+	}
+
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:     lc.call.Pos(),
+		End:     lc.call.End(),
+		Message: msg,
+	})
+}
+
+// enclosingFunc returns the index in stack of the innermost function
+// declaration or literal, or -1.
+func enclosingFunc(stack []ast.Node) int {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return i
+		}
+	}
+	return -1
+}
+
+// inGoroutine reports whether the innermost enclosing function is a function
+// literal started by a go statement.
+func inGoroutine(stack []ast.Node) bool {
+	i := enclosingFunc(stack)
+	if i < 2 {
+		return false
+	}
+	if _, ok := stack[i].(*ast.FuncLit); !ok {
+		return false
+	}
+	call, ok := stack[i-1].(*ast.CallExpr)
+	if !ok || call.Fun != stack[i] {
+		return false
+	}
+	_, ok = stack[i-2].(*ast.GoStmt)
+	return ok
+}
+
+// inInit reports whether the innermost enclosing function declaration is a
+// package init function.
+func inInit(stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if fd, ok := stack[i].(*ast.FuncDecl); ok {
+			return fd.Recv == nil && fd.Name.Name == "init"
+		}
+	}
+	return false
+}
+
+// deferredBefore reports whether a defer statement runs on every path to the
+// call at the top of stack: one that precedes it in the same block or in an
+// enclosing block of the innermost function. Defers in sibling branches and
+// in nested function literals do not count.
+func deferredBefore(stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		var list []ast.Stmt
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		}
+		for _, s := range list {
+			if s == stack[i+1] {
+				break
+			}
+			if _, ok := s.(*ast.DeferStmt); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isAllowedPackage reports whether path matches one of patterns: an exact
+// import path, or a prefix followed by "/..." that also matches the prefix
+// itself.
+func isAllowedPackage(path string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "/..."); ok {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return true
+			}
+		} else if path == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,452 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/Wladim1r/loglinter/internal/config"
+	"github.com/Wladim1r/loglinter/internal/rules"
+)
+
+// messageFix is the single fix offered for the message of a log call. It
+// applies every fixable rule to the message in a fixed order – sensitive,
+// special, english, format, spelling, lowercase, static – so that the
+// diagnostics of one call offer the same edits instead of conflicting
+// rewrites of a literal, and a single -fix run settles the message.
+//
+// Later steps see the output of earlier ones: sensitive operands and their
+// labels are gone before special characters are removed, emoji are gone
+// before letters are transliterated, spaces left by removed characters are
+// collapsed and transliterated letters are lowercased. The static step
+// comes last and builds the constant message from the cleaned text.
+type messageFix struct {
+	pass *analysis.Pass
+	rs   *ruleSet
+	info *pkgInfo
+	lc   logCall
+
+	computed bool
+	// parts are the parts of the message the fix keeps: all of lc.parts
+	// except the operands dropped by the sensitive step.
+	parts []msgPart
+	// fixed holds the value of every kept part after the fix; only string
+	// literals are ever changed.
+	fixed []string
+	// dropped marks the parts of lc.parts that the fix removes, or is nil.
+	dropped []bool
+	// wrapped holds the operands that the fix wraps in the redaction helper,
+	// helper is the helper's qualified name and importEdit adds the import
+	// of its package when the file lacks it.
+	wrapped    map[ast.Expr]bool
+	helper     string
+	importEdit *analysis.TextEdit
+	// static replaces the whole message argument when the static step moves
+	// the dynamic operands into attributes, or is "".
+	static string
+	// applied describes the steps that changed the message, in order.
+	applied []string
+	// resolved records the rules whose diagnostic the fix removes.
+	resolved map[string]bool
+}
+
+// newMessageFix prepares the fix of lc; it is computed on first use.
+func newMessageFix(pass *analysis.Pass, rs *ruleSet, info *pkgInfo, lc logCall) *messageFix {
+	return &messageFix{pass: pass, rs: rs, info: info, lc: lc}
+}
+
+// suggest returns the fix for a diagnostic of rule, or nil when the fix
+// does not remove it.
+func (f *messageFix) suggest(rule string) []analysis.SuggestedFix {
+	f.compute()
+	if !f.resolved[rule] {
+		return nil
+	}
+	return f.suggestion()
+}
+
+// suggestAt returns the fix for a diagnostic of a rule that reports each
+// problem at its part, such as format or spelling, or nil when the part is
+// not a string literal the fix rewrites.
+func (f *messageFix) suggestAt(part msgPart, fixable bool) []analysis.SuggestedFix {
+	f.compute()
+	if !fixable || part.lit == nil {
+		return nil
+	}
+	return f.suggestion()
+}
+
+// suggestion returns the edits of the fix, or nil when it changes nothing.
+func (f *messageFix) suggestion() []analysis.SuggestedFix {
+	if f.static != "" {
+		edits := []analysis.TextEdit{{Pos: f.lc.msgArg.Pos(), End: f.lc.msgArg.End(), NewText: []byte(f.static)}}
+		if len(f.wrapped) > 0 && f.importEdit != nil {
+			edits = append(edits, *f.importEdit)
+		}
+		return []analysis.SuggestedFix{{Message: joinFixMessages(f.applied), TextEdits: edits}}
+	}
+
+	var edits []analysis.TextEdit
+	for i, part := range f.parts {
+		if part.lit != nil && f.fixed[i] != part.value {
+			edits = append(edits, literalFix(part.lit, f.fixed[i]))
+		}
+		if f.wrapped[part.expr] {
+			edits = append(edits,
+				analysis.TextEdit{Pos: part.expr.Pos(), End: part.expr.Pos(), NewText: []byte(f.helper + "(")},
+				analysis.TextEdit{Pos: part.expr.End(), End: part.expr.End(), NewText: []byte(")")},
+			)
+		}
+	}
+	if len(f.wrapped) > 0 && f.importEdit != nil {
+		edits = append(edits, *f.importEdit)
+	}
+	edits = append(edits, dropEdits(f.lc.parts, f.dropped)...)
+	if len(edits) == 0 {
+		return nil
+	}
+	return []analysis.SuggestedFix{{Message: joinFixMessages(f.applied), TextEdits: edits}}
+}
+
+// dropEdits removes the runs of parts marked in removed, each with the "+"
+// that joins it to the rest of the message.
+func dropEdits(parts []msgPart, removed []bool) []analysis.TextEdit {
+	var edits []analysis.TextEdit
+	for i := 0; i < len(removed); {
+		if !removed[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(parts) && removed[j] {
+			j++
+		}
+		if i > 0 {
+			edits = append(edits, analysis.TextEdit{Pos: parts[i-1].expr.End(), End: parts[j-1].expr.End()})
+		} else {
+			edits = append(edits, analysis.TextEdit{Pos: parts[0].expr.Pos(), End: parts[j].expr.Pos()})
+		}
+		i = j
+	}
+	return edits
+}
+
+// compute runs the fix steps of the enabled rules and records which
+// diagnostics the result resolves.
+func (f *messageFix) compute() {
+	if f.computed {
+		return
+	}
+	f.computed = true
+	f.resolved = make(map[string]bool)
+	f.parts = f.lc.parts
+	f.fixed = make([]string, len(f.parts))
+	for i, part := range f.parts {
+		f.fixed[i] = part.value
+	}
+
+	rs := f.rs
+	if rs.sensitive != nil {
+		f.redact()
+	}
+	if rs.special != nil && rs.special.Find(f.text()).Message != "" {
+		f.step("Remove emoji and noisy special characters", func(s string) (string, bool) {
+			return cleanSpecialMessage(s, rs.allowedSpecialChars), true
+		})
+	}
+	if rs.english != nil {
+		f.transliterate()
+	}
+	if rs.format != nil {
+		f.fixFormat()
+	}
+	if rs.spelling != nil {
+		f.fixSpelling()
+	}
+	if rs.lowercase {
+		f.lowercase()
+	}
+
+	msg := f.text()
+	f.resolved[config.RuleLowercase] = rs.lowercase && rules.CheckLowercase(msg) == ""
+	f.resolved[config.RuleSpecial] = rs.special != nil && rs.special.Find(msg).Message == ""
+	if rs.english != nil {
+		f.resolved[config.RuleEnglish] = true
+		for i, part := range f.parts {
+			if part.constant && rs.english.Offending(f.fixed[i]) >= 0 {
+				f.resolved[config.RuleEnglish] = false
+			}
+		}
+	}
+	if rs.static {
+		f.moveToAttrs()
+	}
+}
+
+// moveToAttrs replaces the message by its constant text, as fixed by the
+// earlier steps, and passes the dynamic operands as attributes after it.
+// Operands wrapped by the sensitive step stay wrapped. A message left
+// without dynamic operands by the sensitive step needs no rewrite.
+func (f *messageFix) moveToAttrs() {
+	if !hasDynamicPart(f.parts) {
+		f.resolved[config.RuleStatic] = true
+		return
+	}
+	attr, _ := staticAttr(f.pass, f.lc)
+	if attr == nil || f.lc.call.Ellipsis.IsValid() {
+		return
+	}
+	parts := slices.Clone(f.parts)
+	for i := range parts {
+		parts[i].value = f.fixed[i]
+	}
+	text, ok := staticRewrite(f.pass, parts, attr, func(e ast.Expr) string {
+		if f.wrapped[e] {
+			return f.helper + "(" + types.ExprString(e) + ")"
+		}
+		return types.ExprString(e)
+	})
+	if !ok {
+		return
+	}
+	f.static = text
+	f.applied = append(f.applied, "Move the dynamic values into attributes")
+	f.resolved[config.RuleStatic] = true
+}
+
+// redact applies the fix of the sensitive rule to the operands of the
+// message: it wraps them in the redaction helper or, without one, drops them
+// with their labels. The step is skipped unless it settles the diagnostic.
+func (f *messageFix) redact() {
+	r := redactor{pass: f.pass, rs: f.rs, info: f.info, lc: f.lc}
+	ops := r.operands()
+	if msg, fullExpr := r.text(ops, func(op sensitiveOperand) bool { return op.redacted }); f.rs.sensitive.Find(msg, fullExpr).Message == "" {
+		return
+	}
+	if msg, fullExpr := r.text(ops, func(sensitiveOperand) bool { return true }); f.rs.sensitive.Find(msg, fullExpr).Message != "" {
+		return
+	}
+	var pending []sensitiveOperand
+	var names []string
+	for _, op := range ops {
+		if !op.redacted {
+			pending = append(pending, op)
+			names = append(names, exprToString(f.pass, f.lc.parts[op.part].expr))
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	if f.rs.redactPackage != "" {
+		name, edit, ok := r.helperImport()
+		if !ok {
+			return
+		}
+		f.helper = name + "." + f.rs.redactFunction
+		f.importEdit = edit
+		f.wrapped = make(map[ast.Expr]bool)
+		for _, op := range pending {
+			f.wrapped[f.lc.parts[op.part].expr] = true
+		}
+		f.applied = append(f.applied, fmt.Sprintf("Wrap %s in %s", strings.Join(names, ", "), f.helper))
+		f.resolved[config.RuleSensitive] = true
+		return
+	}
+
+	values, removed, ok := r.dropOperands(pending)
+	if !ok {
+		return
+	}
+	var parts []msgPart
+	var fixed, exprs []string
+	var text strings.Builder
+	for i, part := range f.lc.parts {
+		if removed[i] {
+			continue
+		}
+		parts = append(parts, part)
+		fixed = append(fixed, values[i])
+		exprs = append(exprs, exprToString(f.pass, part.expr))
+		if part.constant {
+			text.WriteString(values[i])
+		}
+	}
+	// A label kept as a status, as in "token present", must not be flagged
+	// again.
+	if f.rs.sensitive.Find(text.String(), strings.Join(exprs, " + ")).Message != "" {
+		return
+	}
+	f.parts, f.fixed, f.dropped = parts, fixed, removed
+	f.applied = append(f.applied, fmt.Sprintf("Drop %s from the message", strings.Join(names, ", ")))
+	f.resolved[config.RuleSensitive] = true
+}
+
+// text returns the constant text of the message as fixed so far.
+func (f *messageFix) text() string {
+	var b strings.Builder
+	for i, part := range f.parts {
+		if part.constant {
+			b.WriteString(f.fixed[i])
+		}
+	}
+	return b.String()
+}
+
+// step applies fn to every string literal of the message. The step is
+// dropped when fn fails for a literal, when it changes nothing or when it
+// would leave the message blank.
+func (f *messageFix) step(message string, fn func(s string) (string, bool)) {
+	next := slices.Clone(f.fixed)
+	for i, part := range f.parts {
+		if part.lit == nil {
+			continue
+		}
+		s, ok := fn(next[i])
+		if !ok {
+			return
+		}
+		next[i] = s
+	}
+	f.commit(next, message)
+}
+
+// commit replaces the fixed values by next unless that changes nothing or
+// leaves the message blank; the fix must never empty a message.
+func (f *messageFix) commit(next []string, messages ...string) {
+	if slices.Equal(next, f.fixed) {
+		return
+	}
+	blank := true
+	for i, part := range f.parts {
+		if !part.constant || strings.TrimSpace(next[i]) != "" {
+			blank = false
+		}
+	}
+	if blank {
+		return
+	}
+	f.fixed = next
+	f.applied = append(f.applied, messages...)
+}
+
+// transliterate spells the letters the english rule rejects in Latin. It
+// changes nothing unless every offending part is a string literal whose
+// letters are all covered by the transliteration tables: a fix that leaves
+// the diagnostic in place is not offered.
+func (f *messageFix) transliterate() {
+	rs := f.rs
+	for i, part := range f.parts {
+		if part.constant && part.lit == nil && rs.english.Offending(f.fixed[i]) >= 0 {
+			return
+		}
+	}
+	f.step("Transliterate the message to Latin letters", func(s string) (string, bool) {
+		if rs.english.Offending(s) < 0 {
+			return s, true
+		}
+		return rs.transliterator.Transliterate(s, rs.english)
+	})
+}
+
+// fixFormat applies the fixes of the format rule to the string literals
+// until none is left. Each fix is computed for the current text, so fixes of
+// the same literal build on each other.
+func (f *messageFix) fixFormat() {
+	next := slices.Clone(f.fixed)
+	var messages []string
+	// Every fix removes a problem for good; the bound only guards against
+	// fixes that undo each other.
+	for range 2 * len(formatFixMessages) * len(next) {
+		parts := make([]rules.FormatPart, len(next))
+		for i, p := range f.parts {
+			parts[i] = rules.FormatPart{Text: next[i], Const: p.constant}
+		}
+		issues := f.rs.format.Check(parts)
+		i := slices.IndexFunc(issues, func(issue rules.FormatIssue) bool {
+			return f.parts[issue.Part].lit != nil
+		})
+		if i < 0 {
+			break
+		}
+		issue := issues[i]
+		next[issue.Part] = issue.Fixed
+		if msg := formatFixMessages[issue.Check]; !slices.Contains(messages, msg) {
+			messages = append(messages, msg)
+		}
+	}
+	f.commit(next, messages...)
+}
+
+// fixSpelling replaces every misspelled word that has a single close match.
+func (f *messageFix) fixSpelling() {
+	next := slices.Clone(f.fixed)
+	var messages []string
+	for i, part := range f.parts {
+		if part.lit == nil {
+			continue
+		}
+		issues := f.rs.spelling.Check(next[i])
+		// Replace from the end so that earlier offsets stay valid.
+		s := next[i]
+		for j := len(issues) - 1; j >= 0; j-- {
+			if issue := issues[j]; issue.Fixed != "" {
+				s = s[:issue.Offset] + issue.Suggestions[0] + s[issue.Offset+len(issue.Word):]
+			}
+		}
+		next[i] = s
+		for _, issue := range issues {
+			if issue.Fixed != "" {
+				messages = append(messages, fmt.Sprintf("Replace %q with %q", issue.Word, issue.Suggestions[0]))
+			}
+		}
+	}
+	f.commit(next, messages...)
+}
+
+// lowercase lowercases the first letter of the message when it is in a
+// string literal.
+func (f *messageFix) lowercase() {
+	next := slices.Clone(f.fixed)
+	for i, part := range f.parts {
+		if !part.constant {
+			continue
+		}
+		s := next[i]
+		start := len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+		if start == len(s) {
+			continue
+		}
+		if part.lit == nil {
+			return
+		}
+		r, size := utf8.DecodeRuneInString(s[start:])
+		next[i] = s[:start] + string(unicode.ToLower(r)) + s[start+size:]
+		break
+	}
+	f.commit(next, "Lowercase the first letter")
+}
+
+// joinFixMessages describes several fix steps in one sentence, such as
+// "Transliterate the message to Latin letters and lowercase the first
+// letter".
+func joinFixMessages(messages []string) string {
+	out := make([]string, len(messages))
+	for i, msg := range messages {
+		if i > 0 {
+			r, size := utf8.DecodeRuneInString(msg)
+			msg = string(unicode.ToLower(r)) + msg[size:]
+		}
+		out[i] = msg
+	}
+	if len(out) <= 1 {
+		return strings.Join(out, "")
+	}
+	return strings.Join(out[:len(out)-1], ", ") + " and " + out[len(out)-1]
+}
@@ -0,0 +1,35 @@
+package analyzer
+
+import (
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/Wladim1r/loglinter/internal/rules"
+)
+
+// formatFixMessages describes the suggested fix of each format sub-check.
+var formatFixMessages = map[string]string{
+	rules.FormatTrailingPeriod: "Remove the trailing period",
+	rules.FormatWhitespace:     "Trim the whitespace",
+	rules.FormatControlChars:   "Replace the control characters with a space",
+	rules.FormatDoubleSpaces:   "Collapse the repeated spaces",
+}
+
+// checkFormat runs the format rule over the parts of the message. Each
+// violation is reported at the offending characters; violations in string
+// literals get the fix of the message, which rewrites each literal where the
+// problem is.
+func checkFormat(pass *analysis.Pass, f *rules.Format, lc logCall, fix *messageFix) {
+	parts := make([]rules.FormatPart, len(lc.parts))
+	for i, p := range lc.parts {
+		parts[i] = rules.FormatPart{Text: p.value, Const: p.constant}
+	}
+
+	for _, issue := range f.Check(parts) {
+		part := lc.parts[issue.Part]
+		d := analysis.Diagnostic{Message: issue.Message}
+		pos, end := partRange(part, issue.Start, issue.End)
+		lc.pointAt(&d, pos, end)
+		d.SuggestedFixes = fix.suggestAt(part, true)
+		reportDiagnostic(pass, d)
+	}
+}
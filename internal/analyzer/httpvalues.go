@@ -0,0 +1,258 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// httpValue describes a net/http or net/url type whose textual form may
+// contain full URLs (with query-string tokens), headers or bodies.
+type httpValue struct {
+	// name is the type as written in diagnostics.
+	name string
+	// advice suggests a redacted alternative; %[1]s is the logged
+	// expression.
+	advice string
+}
+
+// httpValueTypes maps "package.Type" to its description. Pointer and value
+// forms are both recognised.
+var httpValueTypes = map[string]httpValue{
+	"net/url.Error": {
+		name:   "*url.Error",
+		advice: "log %[1]s.Op, %[1]s.Err and a redacted URL instead",
+	},
+	"net/url.URL": {
+		name:   "url.URL",
+		advice: "log %[1]s.Redacted() instead",
+	},
+	"net/http.Request": {
+		name:   "*http.Request",
+		advice: "log %[1]s.Method and %[1]s.URL.Redacted() instead",
+	},
+	"net/http.Response": {
+		name:   "*http.Response",
+		advice: "log %[1]s.StatusCode instead",
+	},
+	"net/http.Header": {
+		name:   "http.Header",
+		advice: "log selected header values instead of the whole %[1]s",
+	},
+}
+
+// pkgInfo holds information computed once per pass for rules that look
+// beyond the log call itself.
+type pkgInfo struct {
+	// errAssigns lists the assignments to the error variables that are
+	// assigned from a call into net/http or net/url somewhere in the
+	// package.
+	errAssigns map[*types.Var][]errAssign
+	// redactImports records, per file, how the fixes of the sensitive rule
+	// refer to the redaction helper's package.
+	redactImports map[*ast.File]redactImport
+}
+
+func newPkgInfo(pass *analysis.Pass, rs *ruleSet) *pkgInfo {
+	info := &pkgInfo{}
+	if rs.httpValues {
+		info.errAssigns = netErrorAssigns(pass)
+	}
+	if rs.sensitive != nil && rs.redactPackage != "" {
+		info.redactImports = make(map[*ast.File]redactImport)
+	}
+	return info
+}
+
+// isNetPackage reports whether path is net/http or net/url.
+func isNetPackage(path string) bool {
+	return path == "net/http" || path == "net/url"
+}
+
+// errAssign is an assignment to an error variable.
+type errAssign struct {
+	// pos is the end of the assignment; the value is visible after it.
+	pos token.Pos
+	// scope is the block or statement the assignment is in, such as the if
+	// statement of `if _, err := url.Parse(s); err != nil`, or nil at
+	// package level.
+	scope ast.Node
+	// net reports whether the value comes from a net/http or net/url call.
+	net bool
+}
+
+// netErrorAssigns finds the assignments to error variables that are
+// assigned from a net/http or net/url call at least once, such as err in
+// `resp, err := client.Do(req)`. The Error() text of such errors is usually
+// a *url.Error that embeds the request URL.
+func netErrorAssigns(pass *analysis.Pass) map[*types.Var][]errAssign {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	errType := types.Universe.Lookup("error").Type()
+	assigns := make(map[*types.Var][]errAssign)
+	hasNet := make(map[*types.Var]bool)
+
+	record := func(n ast.Node, stack []ast.Node, lhs []ast.Expr, rhs []ast.Expr) {
+		net := false
+		if len(rhs) == 1 {
+			if call, ok := ast.Unparen(rhs[0]).(*ast.CallExpr); ok {
+				callee := typeutil.Callee(pass.TypesInfo, call)
+				net = callee != nil && callee.Pkg() != nil && isNetPackage(callee.Pkg().Path())
+			}
+		}
+		a := errAssign{pos: n.End(), scope: assignScope(stack), net: net}
+		for _, e := range lhs {
+			id, ok := e.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var); ok && types.Identical(v.Type(), errType) {
+				assigns[v] = append(assigns[v], a)
+				hasNet[v] = hasNet[v] || net
+			}
+		}
+	}
+
+	insp.WithStack([]ast.Node{(*ast.AssignStmt)(nil), (*ast.ValueSpec)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			record(n, stack, n.Lhs, n.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, id := range n.Names {
+				lhs[i] = id
+			}
+			record(n, stack, lhs, n.Values)
+		}
+		return true
+	})
+
+	for v := range assigns {
+		if !hasNet[v] {
+			delete(assigns, v)
+		}
+	}
+	return assigns
+}
+
+// assignScope returns the innermost block or statement that encloses the
+// assignment at the top of stack, or nil at package level.
+func assignScope(stack []ast.Node) ast.Node {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause,
+			*ast.IfStmt, *ast.ForStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			return n
+		case *ast.File:
+			return nil
+		}
+	}
+	return nil
+}
+
+// isNetError reports whether the value of v at pos may come from a net/http
+// or net/url call. The assignments that may reach pos are the closest one
+// before pos in a block enclosing pos, which always runs, and every
+// assignment between it and pos, such as one in an if or switch branch. If
+// any of them is such a call, v is treated as a net error.
+func (info *pkgInfo) isNetError(v *types.Var, pos token.Pos) bool {
+	var dominating token.Pos
+	for _, a := range info.errAssigns[v] {
+		if a.pos > pos || a.pos < dominating {
+			continue
+		}
+		if a.scope == nil || (a.scope.Pos() <= pos && pos < a.scope.End()) {
+			dominating = a.pos
+		}
+	}
+	for _, a := range info.errAssigns[v] {
+		if a.net && a.pos >= dominating && a.pos <= pos {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHTTPValues reports arguments of the log call – directly, inside
+// attribute constructors such as slog.Any, or as operands of fmt calls – that
+// are net/http or net/url values, or errors returned by those packages.
+func checkHTTPValues(pass *analysis.Pass, info *pkgInfo, lc logCall) {
+	for _, arg := range append([]ast.Expr{lc.msgArg}, lc.args...) {
+		walkLoggedValues(pass, arg, func(e ast.Expr) bool {
+			if diag := describeHTTPValue(pass, info, e); diag != "" {
+				reportDiagnostic(pass, analysis.Diagnostic{
+					Pos:     e.Pos(),
+					End:     e.End(),
+					Message: diag,
+				})
+				return true
+			}
+			return false
+		})
+	}
+}
+
+// walkLoggedValues calls visit for e and, unless visit reports a match, for
+// the values nested in it that end up in the log output: operands of
+// concatenations and arguments of logging or fmt calls. Receivers and other
+// function arguments are not visited, so req.Method or redact(req) are fine.
+func walkLoggedValues(pass *analysis.Pass, e ast.Expr, visit func(ast.Expr) bool) {
+	if visit(e) {
+		return
+	}
+	switch e := ast.Unparen(e).(type) {
+	case *ast.BinaryExpr:
+		walkLoggedValues(pass, e.X, visit)
+		walkLoggedValues(pass, e.Y, visit)
+	case *ast.CallExpr:
+		callee := typeutil.Callee(pass.TypesInfo, e)
+		if callee == nil || callee.Pkg() == nil {
+			return
+		}
+		if path := callee.Pkg().Path(); path == "fmt" || isSupportedPackage(path) {
+			for _, arg := range e.Args {
+				walkLoggedValues(pass, arg, visit)
+			}
+		}
+	}
+}
+
+// describeHTTPValue returns a diagnostic when e is one of httpValueTypes or
+// an error variable assigned from net/http or net/url, and "" otherwise.
+func describeHTTPValue(pass *analysis.Pass, info *pkgInfo, e ast.Expr) string {
+	src := types.ExprString(e)
+
+	if id, ok := ast.Unparen(e).(*ast.Ident); ok {
+		if v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var); ok && info.isNetError(v, id.Pos()) {
+			return fmt.Sprintf(
+				"log argument %s is an error from net/http or net/url whose text may include the full request URL; "+
+					"log a redacted URL and the underlying error instead", src)
+		}
+	}
+
+	t := pass.TypesInfo.TypeOf(e)
+	if t == nil {
+		return ""
+	}
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	hv, ok := httpValueTypes[named.Obj().Pkg().Path()+"."+named.Obj().Name()]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("log argument %s is a %s which may expose URLs, tokens or headers; %s",
+		src, hv.name, fmt.Sprintf(hv.advice, src))
+}
@@ -0,0 +1,63 @@
+package analyzer
+
+import "strings"
+
+// logLevel is the severity of a log call as implied by its method name.
+type logLevel int
+
+const (
+	levelUnknown logLevel = iota
+	levelDebug
+	levelInfo
+	levelWarn
+	levelError
+	// levelPanic logs and then panics (DPanic only in development mode).
+	levelPanic
+	// levelFatal logs and then exits the process without running deferred
+	// calls.
+	levelFatal
+)
+
+// methodLevel classifies a logging method by name, ignoring the formatting
+// and context suffixes: Infof, Infow, InfoContext and InfoCtx are all
+// levelInfo. The standard library's Print functions log at levelInfo.
+func methodLevel(name string) logLevel {
+	for _, suffix := range []string{"Context", "Ctx"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	switch name {
+	case "Debug", "Debugf", "Debugw", "Debugln":
+		return levelDebug
+	case "Info", "Infof", "Infow", "Infoln", "Print", "Printf", "Println":
+		return levelInfo
+	case "Warn", "Warnf", "Warnw", "Warnln":
+		return levelWarn
+	case "Error", "Errorf", "Errorw", "Errorln":
+		return levelError
+	case "Panic", "Panicf", "Panicw", "Panicln", "DPanic", "DPanicf", "DPanicw", "DPanicln":
+		return levelPanic
+	case "Fatal", "Fatalf", "Fatalw", "Fatalln":
+		return levelFatal
+	}
+	return levelUnknown
+}
+
+// String returns the lower-case level name used in the message catalog, or
+// "" for levelUnknown.
+func (l logLevel) String() string {
+	switch l {
+	case levelDebug:
+		return "debug"
+	case levelInfo:
+		return "info"
+	case levelWarn:
+		return "warn"
+	case levelError:
+		return "error"
+	case levelPanic:
+		return "panic"
+	case levelFatal:
+		return "fatal"
+	}
+	return ""
+}
@@ -0,0 +1,228 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// msgPart is one operand of a message built by string concatenation, e.g.
+// "user " + id + " logged in" has three parts.
+type msgPart struct {
+	expr ast.Expr
+	// lit is set when the operand is a string literal, which fixes can
+	// rewrite.
+	lit *ast.BasicLit
+	// value is the operand's constant value; constant reports whether it has
+	// one. Literals and named string constants are constant.
+	value    string
+	constant bool
+}
+
+// messageParts flattens a chain of string concatenations into its operands,
+// in source order. Any other expression, including a call such as
+// fmt.Sprintf, is a single part.
+func messageParts(pass *analysis.Pass, expr ast.Expr) []msgPart {
+	var parts []msgPart
+	var walk func(e ast.Expr)
+	walk = func(e ast.Expr) {
+		e = ast.Unparen(e)
+		if bin, ok := e.(*ast.BinaryExpr); ok && bin.Op == token.ADD && isStringExpr(pass, bin) {
+			walk(bin.X)
+			walk(bin.Y)
+			return
+		}
+
+		p := msgPart{expr: e}
+		if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			p.lit = lit
+		}
+		if tv, ok := pass.TypesInfo.Types[e]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			p.value, p.constant = constant.StringVal(tv.Value), true
+		}
+		parts = append(parts, p)
+	}
+	walk(expr)
+	return parts
+}
+
+// isStringExpr reports whether e has a string type.
+func isStringExpr(pass *analysis.Pass, e ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[e]
+	if !ok {
+		return false
+	}
+	b, ok := tv.Type.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
+
+// constantText joins the constant parts of a message, skipping the others.
+func constantText(parts []msgPart) string {
+	var b strings.Builder
+	for _, p := range parts {
+		if p.constant {
+			b.WriteString(p.value)
+		}
+	}
+	return b.String()
+}
+
+// hasDynamicPart reports whether any part of the message is not constant.
+func hasDynamicPart(parts []msgPart) bool {
+	for _, p := range parts {
+		if !p.constant {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteLike returns value as Go source in the style of lit: a raw string
+// when lit is raw and value can still be written raw, an interpreted string
+// otherwise.
+func quoteLike(lit *ast.BasicLit, value string) string {
+	if strings.HasPrefix(lit.Value, "`") && !strings.ContainsAny(value, "`\r") {
+		return "`" + value + "`"
+	}
+	return strconv.Quote(value)
+}
+
+// literalFix returns a text edit replacing lit with a literal holding value.
+func literalFix(lit *ast.BasicLit, value string) analysis.TextEdit {
+	return analysis.TextEdit{
+		Pos:     lit.Pos(),
+		End:     lit.End(),
+		NewText: []byte(quoteLike(lit, value)),
+	}
+}
+
+// litOffsets maps the bytes of the value of lit to its source text: offs[i]
+// is the offset, from the opening quote, of the character or escape sequence
+// that produces byte i of the value, and offs[len(value)] is the offset of
+// the closing quote. It returns nil for literals it cannot decode.
+func litOffsets(lit *ast.BasicLit) []int {
+	src := lit.Value
+	if len(src) < 2 {
+		return nil
+	}
+	var offs []int
+	if src[0] == '`' {
+		// Raw strings drop carriage returns and have no escapes.
+		for i := 1; i < len(src)-1; i++ {
+			if src[i] != '\r' {
+				offs = append(offs, i)
+			}
+		}
+		return append(offs, len(src)-1)
+	}
+
+	for i := 1; i < len(src)-1; {
+		r, multibyte, tail, err := strconv.UnquoteChar(src[i:len(src)-1], src[0])
+		if err != nil {
+			return nil
+		}
+		next := len(src) - 1 - len(tail)
+		if src[i] != '\\' {
+			// Characters other than escapes are copied byte for byte.
+			for k := i; k < next; k++ {
+				offs = append(offs, k)
+			}
+			i = next
+			continue
+		}
+		// Every byte an escape produces maps to its backslash; \x and octal
+		// escapes produce a single byte.
+		n := 1
+		if multibyte || r < utf8.RuneSelf {
+			n = utf8.RuneLen(r)
+		}
+		for k := 0; k < n; k++ {
+			offs = append(offs, i)
+		}
+		i = next
+	}
+	return append(offs, len(src)-1)
+}
+
+// partRange returns the source range of bytes [start, end) of the value of
+// part. Offsets inside a string literal map to the exact characters, taking
+// escapes into account; other constant parts, such as named constants, map
+// to the whole expression.
+func partRange(part msgPart, start, end int) (token.Pos, token.Pos) {
+	if part.lit == nil || start < 0 || end < start {
+		return part.expr.Pos(), part.expr.End()
+	}
+	offs := litOffsets(part.lit)
+	if end >= len(offs) {
+		return part.expr.Pos(), part.expr.End()
+	}
+	return part.lit.Pos() + token.Pos(offs[start]), part.lit.Pos() + token.Pos(offs[end])
+}
+
+// textRange returns the source range of bytes [start, end) of the constant
+// text of the message, as joined by constantText. The range may span several
+// parts; it falls back to the whole message argument when the offsets are
+// not in the constant text.
+func (lc logCall) textRange(start, end int) (token.Pos, token.Pos) {
+	if start < 0 || end < start {
+		return lc.msgArg.Pos(), lc.msgArg.End()
+	}
+	pos, endPos := token.NoPos, token.NoPos
+	base := 0
+	for _, part := range lc.parts {
+		if !part.constant {
+			continue
+		}
+		next := base + len(part.value)
+		if !pos.IsValid() && start >= base && start < next {
+			pos, _ = partRange(part, start-base, start-base)
+		}
+		if pos.IsValid() && end > base && end <= next {
+			_, endPos = partRange(part, end-base, end-base)
+			if part.lit == nil {
+				endPos = part.expr.End()
+			}
+			break
+		}
+		base = next
+	}
+	if !pos.IsValid() || !endPos.IsValid() {
+		return lc.msgArg.Pos(), lc.msgArg.End()
+	}
+	return pos, endPos
+}
+
+// identRange returns the source range of the first identifier named name in
+// the message argument, or the whole argument when there is none.
+func (lc logCall) identRange(name string) (token.Pos, token.Pos) {
+	pos, end := lc.msgArg.Pos(), lc.msgArg.End()
+	found := false
+	ast.Inspect(lc.msgArg, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && !found && id.Name == name {
+			pos, end, found = id.Pos(), id.End(), true
+		}
+		return !found
+	})
+	return pos, end
+}
+
+// pointAt narrows d to the range [pos, end) within the message argument.
+// When the range is narrower than the argument, the whole message is attached
+// as related information so that editors still show what was checked.
+func (lc logCall) pointAt(d *analysis.Diagnostic, pos, end token.Pos) {
+	d.Pos, d.End = pos, end
+	if pos == lc.msgArg.Pos() && end == lc.msgArg.End() {
+		return
+	}
+	d.Related = append(d.Related, analysis.RelatedInformation{
+		Pos:     lc.msgArg.Pos(),
+		End:     lc.msgArg.End(),
+		Message: "in this log message",
+	})
+}
@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkLogReturn reports log calls that log an error which the same block
+// then returns unchanged, as in
+//
+//	if err != nil {
+//		slog.Error("query failed", "err", err)
+//		return err
+//	}
+//
+// Every caller up the stack is likely to log the error again. Returning a
+// wrapped error (fmt.Errorf("...: %w", err)) or handling it without
+// returning is fine, as are packages listed as logging boundaries. An
+// assignment to the variable between the log and the return means a
+// different error is returned, so it ends the scan for that variable.
+func checkLogReturn(pass *analysis.Pass, rs *ruleSet, lc logCall) {
+	if isAllowedPackage(pass.Pkg.Path(), rs.logReturnAllowedPackages) {
+		return
+	}
+	stmts, i := enclosingStmtList(lc.stack)
+	if stmts == nil {
+		return
+	}
+
+	reassigned := make(map[*types.Var]bool)
+	for _, s := range stmts[i+1:] {
+		ret, ok := s.(*ast.ReturnStmt)
+		if !ok {
+			markAssigned(pass, s, reassigned)
+			continue
+		}
+		for _, res := range ret.Results {
+			id, ok := ast.Unparen(res).(*ast.Ident)
+			if !ok {
+				continue
+			}
+			v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+			if !ok || reassigned[v] || !types.Implements(v.Type(), errorIface) || !referencesVar(pass, lc.call.Args, v) {
+				continue
+			}
+			reportDiagnostic(pass, analysis.Diagnostic{
+				Pos: lc.call.Pos(),
+				End: lc.call.End(),
+				Message: fmt.Sprintf("%[1]s is logged and then returned unwrapped, so callers will log it again; "+
+					"either handle it here or return it with context, e.g. fmt.Errorf(\"...: %%w\", %[1]s)", v.Name()),
+				Related: []analysis.RelatedInformation{{
+					Pos:     ret.Pos(),
+					End:     ret.End(),
+					Message: "returned here",
+				}},
+			})
+			return
+		}
+	}
+}
+
+// markAssigned records in vars every variable that s assigns to, including
+// assignments in nested blocks.
+func markAssigned(pass *analysis.Pass, s ast.Stmt, vars map[*types.Var]bool) {
+	ast.Inspect(s, func(n ast.Node) bool {
+		if as, ok := n.(*ast.AssignStmt); ok {
+			for _, lhs := range as.Lhs {
+				if id, ok := ast.Unparen(lhs).(*ast.Ident); ok {
+					if v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var); ok {
+						vars[v] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// enclosingStmtList returns the innermost statement list (a block or a
+// switch or select clause) containing the call at the top of stack, and the
+// index of the statement in it that contains the call. It does not cross
+// function boundaries.
+func enclosingStmtList(stack []ast.Node) ([]ast.Stmt, int) {
+	for i := len(stack) - 2; i >= 0; i-- {
+		var list []ast.Stmt
+		switch n := stack[i].(type) {
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil, -1
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		default:
+			continue
+		}
+		for j, s := range list {
+			if s == stack[i+1] {
+				return list, j
+			}
+		}
+	}
+	return nil, -1
+}
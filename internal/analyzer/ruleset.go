@@ -0,0 +1,196 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Wladim1r/loglinter/internal/config"
+	"github.com/Wladim1r/loglinter/internal/rules"
+)
+
+// ruleSet is the compiled, immutable form of a config.Config. It is built
+// once per distinct configuration and shared by every pass that uses it, so
+// no per-package or per-call work is spent on parsing or normalising config.
+type ruleSet struct {
+	lowercase  bool
+	httpValues bool
+	context    bool
+	fatal      bool
+	errorAttr  bool
+	logReturn  bool
+	static     bool
+	// english, special, sensitive, pii, secrets, format, length, duplicate,
+	// eventID and spelling are nil when the rule is disabled.
+	english   *rules.English
+	special   *rules.Special
+	sensitive *rules.Sensitive
+	pii       *rules.PII
+	secrets   *rules.Secrets
+	format    *rules.Format
+	length    *rules.Length
+	duplicate *rules.Duplicate
+	eventID   *rules.EventID
+	spelling  *rules.Spelling
+	// transliterator backs the fix of the english rule; it is nil when the
+	// rule is disabled.
+	transliterator *rules.Transliterator
+	// eventIDLevels are the level names that require an event ID.
+	eventIDLevels map[string]bool
+	// redactPackage and redactFunction name the helper the sensitive
+	// rule's fix wraps values in; both are empty when values are dropped.
+	redactPackage, redactFunction string
+	// allowedSpecialChars is kept for the special-characters auto-fix.
+	allowedSpecialChars string
+	// fatalAllowedPackages are the import path patterns where the fatal
+	// rule is silent.
+	fatalAllowedPackages []string
+	// logReturnAllowedPackages are the logging boundaries where the
+	// logreturn rule is silent.
+	logReturnAllowedPackages []string
+}
+
+// compileRules precomputes everything the rules need from cfg. It fails only
+// for programmatic configs with invalid secret detector or event ID patterns
+// or unknown scripts; config files are validated when loaded.
+func compileRules(cfg *config.Config) (*ruleSet, error) {
+	rs := &ruleSet{
+		lowercase:                cfg.IsRuleEnabled(config.RuleLowercase),
+		httpValues:               cfg.IsRuleEnabled(config.RuleHTTPValues),
+		context:                  cfg.IsRuleEnabled(config.RuleContext),
+		fatal:                    cfg.IsRuleEnabled(config.RuleFatal),
+		errorAttr:                cfg.IsRuleEnabled(config.RuleErrorAttr),
+		logReturn:                cfg.IsRuleEnabled(config.RuleLogReturn),
+		static:                   cfg.IsRuleEnabled(config.RuleStatic),
+		allowedSpecialChars:      cfg.AllowedSpecialChars,
+		fatalAllowedPackages:     cfg.FatalAllowedPackages,
+		logReturnAllowedPackages: cfg.LogReturnAllowedPackages,
+	}
+	if cfg.Redaction.Package != "" && cfg.Redaction.Function != "" {
+		rs.redactPackage, rs.redactFunction = cfg.Redaction.Package, cfg.Redaction.Function
+	}
+	if cfg.IsRuleEnabled(config.RuleEnglish) {
+		runes := make([]rune, 0, len(cfg.AllowedRunes))
+		for _, s := range cfg.AllowedRunes {
+			runes = append(runes, []rune(s)...)
+		}
+		english, err := rules.NewEnglish(cfg.AllowedScripts, runes)
+		if err != nil {
+			return nil, fmt.Errorf("loglinter: %w", err)
+		}
+		rs.english = english
+		rs.transliterator = rules.NewTransliterator(cfg.Transliteration.Tables, cfg.Transliteration.Custom)
+	}
+	if cfg.IsRuleEnabled(config.RuleSpecial) {
+		rs.special = rules.NewSpecial(cfg.AllowedSpecialChars)
+	}
+	if cfg.IsRuleEnabled(config.RuleSensitive) {
+		modes := make(map[string]rules.MatchMode, len(cfg.SensitiveKeywordMatch))
+		for kw, name := range cfg.SensitiveKeywordMatch {
+			// Values are validated by config.Load; unknown ones fall back
+			// to segment matching for programmatic configs.
+			modes[kw], _ = rules.ParseMatchMode(name)
+		}
+		rs.sensitive = rules.NewSensitive(cfg.SensitiveKeywords, cfg.SafeNextWords, modes)
+	}
+	if cfg.IsRuleEnabled(config.RulePII) {
+		rs.pii = rules.NewPII(cfg.PIIDetectors)
+	}
+	if cfg.IsRuleEnabled(config.RuleFormat) {
+		rs.format = rules.NewFormat(cfg.Format)
+	}
+	if cfg.IsRuleEnabled(config.RuleLength) {
+		rs.length = rules.NewLength(cfg.Length.MinLength, cfg.Length.MaxLength)
+	}
+	if cfg.IsRuleEnabled(config.RuleDuplicate) {
+		rs.duplicate = rules.NewDuplicate(cfg.Duplicate.MaxCallSites, cfg.Duplicate.AllowedMessages)
+	}
+	if cfg.IsRuleEnabled(config.RuleEventID) {
+		eventID, err := rules.NewEventID(cfg.EventID.Key, cfg.EventID.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("loglinter: %w", err)
+		}
+		rs.eventID = eventID
+		rs.eventIDLevels = make(map[string]bool, len(cfg.EventID.Levels))
+		for _, level := range cfg.EventID.Levels {
+			rs.eventIDLevels[level] = true
+		}
+	}
+	if cfg.IsRuleEnabled(config.RuleSpelling) {
+		rs.spelling = rules.NewSpelling(cfg.SpellingWords)
+	}
+	if cfg.IsRuleEnabled(config.RuleSecrets) {
+		secrets, err := rules.NewSecrets(rules.SecretsOptions{
+			Detectors:        cfg.Secrets.Detectors,
+			EntropyThreshold: cfg.Secrets.EntropyThreshold,
+			MinLength:        cfg.Secrets.MinLength,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("loglinter: %w", err)
+		}
+		rs.secrets = secrets
+	}
+	return rs, nil
+}
+
+// ruleSetCache loads and compiles configuration at most once per run: config
+// files are discovered once per package directory, and each distinct chain of
+// files is parsed and compiled once. It is safe for concurrent use by passes
+// running in parallel.
+type ruleSetCache struct {
+	mu   sync.Mutex
+	dirs map[string][]string
+	sets map[string]*cachedRuleSet
+}
+
+type cachedRuleSet struct {
+	once sync.Once
+	rs   *ruleSet
+	err  error
+}
+
+func newRuleSetCache() *ruleSetCache {
+	return &ruleSetCache{
+		dirs: make(map[string][]string),
+		sets: make(map[string]*cachedRuleSet),
+	}
+}
+
+// forDir returns the rule set for a package located in dir.
+func (c *ruleSetCache) forDir(dir string) (*ruleSet, error) {
+	c.mu.Lock()
+	paths, ok := c.dirs[dir]
+	c.mu.Unlock()
+	if !ok {
+		paths = config.Discover(dir)
+		c.mu.Lock()
+		c.dirs[dir] = paths
+		c.mu.Unlock()
+	}
+	return c.forFiles(paths)
+}
+
+// forFiles returns the rule set for the given chain of config files.
+func (c *ruleSetCache) forFiles(paths []string) (*ruleSet, error) {
+	key := strings.Join(paths, "\x00")
+
+	c.mu.Lock()
+	entry, ok := c.sets[key]
+	if !ok {
+		entry = &cachedRuleSet{}
+		c.sets[key] = entry
+	}
+	c.mu.Unlock()
+
+	// Loading happens outside the lock so unrelated chains do not wait for
+	// each other; once makes concurrent callers share a single load.
+	entry.once.Do(func() {
+		cfg, err := config.LoadFiles(paths...)
+		if err != nil {
+			entry.err = err
+			return
+		}
+		entry.rs, entry.err = compileRules(cfg)
+	})
+	return entry.rs, entry.err
+}
@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Wladim1r/loglinter/internal/config"
+)
+
+// benchModule creates a module with a nested config chain and returns the
+// package directory.
+func benchModule(b *testing.B) string {
+	b.Helper()
+	root := b.TempDir()
+	files := map[string]string{
+		"go.mod":                 "module bench\n",
+		".loglinter.yaml":        "sensitive_keywords: [my_secret, db_pass]\nallowed_special_chars: \"!\"\n",
+		"svc/.loglinter.yaml":    "rules:\n  lowercase: false\n",
+		"svc/handler/handler.go": "package handler\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return filepath.Join(root, "svc", "handler")
+}
+
+// BenchmarkRuleSet_PerPackage measures the old behaviour of resolving,
+// parsing and compiling the configuration for every analysed package.
+func BenchmarkRuleSet_PerPackage(b *testing.B) {
+	dir := benchModule(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cfg, err := config.Resolve(dir)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, _ = compileRules(cfg)
+	}
+}
+
+// BenchmarkRuleSet_Cached measures the lookup cost once the rule set has been
+// compiled for the first package of a run.
+func BenchmarkRuleSet_Cached(b *testing.B) {
+	dir := benchModule(b)
+	cache := newRuleSetCache()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cache.forDir(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
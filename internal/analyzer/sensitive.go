@@ -0,0 +1,461 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/Wladim1r/loglinter/internal/config"
+)
+
+// checkSensitive runs the sensitive rule over the message and, for slog and
+// zap calls, over the attributes. Values that carry sensitive data – message
+// operands such as tok in "token: " + tok and attribute values such as pw in
+// "password", pw – get a fix that wraps them in the configured redaction
+// helper or, without one, drops them; for message operands it is part of the
+// fix of the message. Values already wrapped in the helper are redacted and
+// do not count.
+func checkSensitive(pass *analysis.Pass, rs *ruleSet, info *pkgInfo, lc logCall, fix *messageFix) {
+	r := redactor{pass: pass, rs: rs, info: info, lc: lc}
+	ops := r.operands()
+
+	msg, fullExpr := r.text(ops, func(op sensitiveOperand) bool { return op.redacted })
+	if f := rs.sensitive.Find(msg, fullExpr); f.Message != "" {
+		d := analysis.Diagnostic{
+			Message:        f.Message,
+			SuggestedFixes: fix.suggest(config.RuleSensitive),
+		}
+		pos, end := lc.textRange(f.Start, f.End)
+		if f.Ident != "" {
+			pos, end = lc.identRange(f.Ident)
+		}
+		lc.pointAt(&d, pos, end)
+		reportDiagnostic(pass, d)
+	}
+
+	sel, ok := lc.call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	for _, a := range logAttrs(pass, loggerFamily(pass, sel), lc.args) {
+		if a.value == nil || r.isRedacted(a.value) {
+			continue
+		}
+		if tv, ok := pass.TypesInfo.Types[a.value]; !ok || tv.Value != nil {
+			// Constant values are left to the pii and secrets rules.
+			continue
+		}
+		f := rs.sensitive.Find(a.key, exprToString(pass, a.value))
+		if f.Message == "" {
+			continue
+		}
+		reportDiagnostic(pass, analysis.Diagnostic{
+			Pos:            a.value.Pos(),
+			End:            a.value.End(),
+			Message:        fmt.Sprintf("log attribute %q may contain sensitive data (keyword %q)", a.key, f.Keyword),
+			SuggestedFixes: r.fixAttr(a),
+		})
+	}
+}
+
+// sensitiveOperand is a dynamic operand of the message that carries
+// sensitive data or is already redacted.
+type sensitiveOperand struct {
+	// part is the index of the operand in lc.parts.
+	part int
+	// labelStart and labelEnd are the byte range, in the value of the part
+	// before, of the word that names the operand, such as "token" in
+	// "token: ". Both are -1 when the operand has no such label.
+	labelStart, labelEnd int
+	redacted             bool
+}
+
+// redactor finds the sensitive values of a log call and builds the fixes
+// that redact them.
+type redactor struct {
+	pass *analysis.Pass
+	rs   *ruleSet
+	info *pkgInfo
+	lc   logCall
+}
+
+// operands returns the dynamic operands of the message that are redacted,
+// named like sensitive data, or labelled by a sensitive word of the constant
+// part before them.
+func (r redactor) operands() []sensitiveOperand {
+	parts := r.lc.parts
+	if len(parts) < 2 {
+		return nil
+	}
+	var ops []sensitiveOperand
+	for i, part := range parts {
+		if part.constant {
+			continue
+		}
+		op := sensitiveOperand{part: i, labelStart: -1, labelEnd: -1, redacted: r.isRedacted(part.expr)}
+		if i > 0 && parts[i-1].constant {
+			op.labelStart, op.labelEnd = r.label(parts[i-1].value)
+		}
+		named := r.rs.sensitive.Find("", exprToString(r.pass, part.expr)).Message != ""
+		if op.redacted || named || op.labelStart >= 0 {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// label returns the byte range of the last word of s when it is a sensitive
+// keyword, ignoring trailing separators as in "token: " or "password=", or
+// -1, -1.
+func (r redactor) label(s string) (int, int) {
+	end := len(strings.TrimRight(s, " :="))
+	start := strings.LastIndexFunc(s[:end], func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_'
+	}) + 1
+	if start == end || r.rs.sensitive.Find(s[start:end], "").Message == "" {
+		return -1, -1
+	}
+	return start, end
+}
+
+// isRedacted reports whether e is a call to the configured redaction helper.
+func (r redactor) isRedacted(e ast.Expr) bool {
+	if r.rs.redactPackage == "" {
+		return false
+	}
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != r.rs.redactFunction {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	pkg, ok := r.pass.TypesInfo.Uses[id].(*types.PkgName)
+	return ok && pkg.Imported().Path() == r.rs.redactPackage
+}
+
+// text returns the message text and expression checked by the sensitive
+// rule when the operands selected by excused are redacted: their labels are
+// blanked out of the text, keeping offsets intact, and they are left out of
+// the expression.
+func (r redactor) text(ops []sensitiveOperand, excused func(sensitiveOperand) bool) (msg, fullExpr string) {
+	parts := r.lc.parts
+	values := make([]string, len(parts))
+	skip := make([]bool, len(parts))
+	changed := false
+	for i, part := range parts {
+		values[i] = part.value
+	}
+	for _, op := range ops {
+		if !excused(op) {
+			continue
+		}
+		changed = true
+		skip[op.part] = true
+		if op.labelStart >= 0 {
+			prev := values[op.part-1]
+			values[op.part-1] = prev[:op.labelStart] + strings.Repeat(" ", op.labelEnd-op.labelStart) + prev[op.labelEnd:]
+		}
+	}
+	if !changed {
+		return r.lc.msgLiteral, r.lc.fullExpr
+	}
+
+	var b strings.Builder
+	var exprs []string
+	for i, part := range parts {
+		if part.constant {
+			b.WriteString(values[i])
+		}
+		if !skip[i] {
+			exprs = append(exprs, exprToString(r.pass, part.expr))
+		}
+	}
+	return b.String(), strings.Join(exprs, " + ")
+}
+
+// dropOperands returns the values of the message parts once the operands
+// are removed, together with their labels and the separators around them,
+// and the parts left out: "login ok, token: " + tok and "login ok: " + tok
+// both become "login ok". A message that is nothing but a label and its
+// operand keeps the label as a status, so "token: " + tok becomes "token
+// present". It fails when an operand is woven into a sentence, as in
+// "signed in with " + password, where dropping it would leave a dangling
+// word, or when text to trim is not in a string literal.
+func (r redactor) dropOperands(ops []sensitiveOperand) ([]string, []bool, bool) {
+	parts := r.lc.parts
+	values := make([]string, len(parts))
+	removed := make([]bool, len(parts))
+	for i, part := range parts {
+		values[i] = part.value
+	}
+	for _, op := range ops {
+		removed[op.part] = true
+		if op.part == 0 || !parts[op.part-1].constant {
+			continue
+		}
+		prev := op.part - 1
+		var trimmed string
+		if op.labelStart >= 0 {
+			trimmed = strings.TrimRight(values[prev][:op.labelStart], dropSeparators)
+		} else {
+			trimmed = strings.TrimRight(values[prev], dropSeparators)
+			sep := values[prev][len(trimmed):]
+			if strings.TrimSpace(sep) == "" && trimmed != "" {
+				// Nothing but spaces after a word: the operand is part of
+				// the sentence.
+				return nil, nil, false
+			}
+		}
+		if trimmed == values[prev] {
+			continue
+		}
+		if parts[prev].lit == nil {
+			return nil, nil, false
+		}
+		values[prev] = trimmed
+		removed[prev] = trimmed == ""
+	}
+	// Leading operands leave the separators of the text after them.
+	if first := slices.Index(removed, false); first > 0 && parts[first].constant {
+		trimmed := strings.TrimLeft(values[first], dropSeparators)
+		if trimmed != values[first] {
+			if parts[first].lit == nil {
+				return nil, nil, false
+			}
+			values[first] = trimmed
+		}
+	}
+
+	for i, part := range parts {
+		if !removed[i] && (!part.constant || strings.TrimSpace(values[i]) != "") {
+			return values, removed, true
+		}
+	}
+	return r.keepLabel(ops)
+}
+
+// keepLabel drops the operand of a message that is nothing but a label and
+// the operand, turning the label into a status: "token: " + tok becomes
+// "token present".
+func (r redactor) keepLabel(ops []sensitiveOperand) ([]string, []bool, bool) {
+	parts := r.lc.parts
+	if len(ops) != 1 || ops[0].labelStart < 0 || parts[ops[0].part-1].lit == nil {
+		return nil, nil, false
+	}
+	op := ops[0]
+	values := make([]string, len(parts))
+	removed := make([]bool, len(parts))
+	for i, part := range parts {
+		values[i] = part.value
+	}
+	values[op.part-1] = values[op.part-1][:op.labelEnd] + " present"
+	removed[op.part] = true
+	return values, removed, true
+}
+
+// dropSeparators are the characters trimmed around a dropped operand.
+const dropSeparators = " :=,;-"
+
+// fixAttr redacts the value of a sensitive attribute.
+func (r redactor) fixAttr(a logAttr) []analysis.SuggestedFix {
+	if r.rs.redactPackage != "" {
+		// The helper is assumed to take a string, as redact.String does.
+		tv := r.pass.TypesInfo.Types[a.value]
+		if b, ok := tv.Type.Underlying().(*types.Basic); !ok || b.Info()&types.IsString == 0 {
+			return nil
+		}
+		return r.wrap(fmt.Sprintf("Wrap the value of %q in", a.key), a.value)
+	}
+
+	// Drop the attribute with the comma before it.
+	args := r.lc.call.Args
+	i := slices.Index(args, a.args[0])
+	if i < 1 || r.lc.call.Ellipsis.IsValid() {
+		return nil
+	}
+	return r.suggestion(fmt.Sprintf("Drop the %q attribute", a.key), []analysis.TextEdit{{
+		Pos: args[i-1].End(),
+		End: a.args[len(a.args)-1].End(),
+	}})
+}
+
+// wrap returns a fix wrapping each of exprs in a call to the redaction
+// helper, adding the import of its package when the file lacks it. The
+// message is completed with the helper's name.
+func (r redactor) wrap(message string, exprs ...ast.Expr) []analysis.SuggestedFix {
+	name, imp, ok := r.helperImport()
+	if !ok {
+		return nil
+	}
+	helper := name + "." + r.rs.redactFunction
+	var edits []analysis.TextEdit
+	for _, e := range exprs {
+		edits = append(edits,
+			analysis.TextEdit{Pos: e.Pos(), End: e.Pos(), NewText: []byte(helper + "(")},
+			analysis.TextEdit{Pos: e.End(), End: e.End(), NewText: []byte(")")},
+		)
+	}
+	if imp != nil {
+		edits = append(edits, *imp)
+	}
+	return r.suggestion(message+" "+helper, edits)
+}
+
+// helperImport returns the name under which the file refers to the package
+// of the redaction helper at the call and, when the file does not import it
+// yet, the edit adding the import. It fails when the name is shadowed at the
+// call.
+func (r redactor) helperImport() (string, *analysis.TextEdit, bool) {
+	if len(r.lc.stack) == 0 {
+		return "", nil, false
+	}
+	file, ok := r.lc.stack[0].(*ast.File)
+	if !ok {
+		return "", nil, false
+	}
+	imp, ok := r.info.redactImports[file]
+	if !ok {
+		imp = r.fileImport(file)
+		r.info.redactImports[file] = imp
+	}
+	if imp.edit != nil {
+		// The name is free everywhere in the file.
+		return imp.name, imp.edit, true
+	}
+	pos := r.lc.call.Pos()
+	scope := r.pass.Pkg.Scope().Innermost(pos)
+	if scope == nil {
+		return "", nil, false
+	}
+	_, obj := scope.LookupParent(imp.name, pos)
+	if pkg, ok := obj.(*types.PkgName); !ok || pkg.Imported().Path() != r.rs.redactPackage {
+		return "", nil, false
+	}
+	return imp.name, nil, true
+}
+
+// redactImport is how a file refers to the package of the redaction helper:
+// the name, and the edit adding the import when the file lacks it. Every fix
+// in the file uses the same name and the same edit, so that fixes applied
+// together add a single import.
+type redactImport struct {
+	name string
+	edit *analysis.TextEdit
+}
+
+// fileImport chooses how file refers to the package of the redaction
+// helper. An existing import is reused. A new import takes the package name,
+// or the name with a number appended when it is declared anywhere in the
+// file, so that it is free at every call the fixes touch; it is aliased when
+// the name cannot be told from the path, as for "gopkg.in/redact.v2".
+func (r redactor) fileImport(file *ast.File) redactImport {
+	pkgPath := r.rs.redactPackage
+	if name := importName([]ast.Node{file}, pkgPath); name != "" {
+		return redactImport{name: name}
+	}
+
+	base := guessPackageName(pkgPath)
+	name := base
+	for n := 2; !r.freeInFile(file, name); n++ {
+		name = base + strconv.Itoa(n)
+	}
+
+	spec := strconv.Quote(pkgPath)
+	if name != path.Base(pkgPath) {
+		spec = name + " " + spec
+	}
+	var edit analysis.TextEdit
+	switch last := lastImportDecl(file); {
+	case last == nil:
+		edit = analysis.TextEdit{Pos: file.Name.End(), End: file.Name.End(), NewText: []byte("\n\nimport " + spec)}
+	case last.Lparen.IsValid():
+		// Standard library imports are followed by a new group.
+		text := "\t" + spec + "\n"
+		if n := len(last.Specs); n > 0 && isStdImport(last.Specs[n-1].(*ast.ImportSpec)) {
+			text = "\n" + text
+		}
+		edit = analysis.TextEdit{Pos: last.Rparen, End: last.Rparen, NewText: []byte(text)}
+	default:
+		edit = analysis.TextEdit{Pos: last.End(), End: last.End(), NewText: []byte("\nimport " + spec)}
+	}
+	return redactImport{name: name, edit: &edit}
+}
+
+// freeInFile reports whether name is not a predeclared identifier, a
+// package-level name, an import of file or declared anywhere in file.
+func (r redactor) freeInFile(file *ast.File, name string) bool {
+	if types.Universe.Lookup(name) != nil || r.pass.Pkg.Scope().Lookup(name) != nil {
+		return false
+	}
+	for _, spec := range file.Imports {
+		if pkg := r.pass.TypesInfo.PkgNameOf(spec); pkg != nil && pkg.Name() == name {
+			return false
+		}
+	}
+	for id, obj := range r.pass.TypesInfo.Defs {
+		if obj != nil && id.Name == name && file.Pos() <= id.Pos() && id.Pos() < file.End() {
+			return false
+		}
+	}
+	return true
+}
+
+// suggestion wraps edits in a fix, or returns nil when there are none.
+func (r redactor) suggestion(message string, edits []analysis.TextEdit) []analysis.SuggestedFix {
+	if len(edits) == 0 {
+		return nil
+	}
+	return []analysis.SuggestedFix{{Message: message, TextEdits: edits}}
+}
+
+// lastImportDecl returns the last import declaration of file, or nil.
+func lastImportDecl(file *ast.File) *ast.GenDecl {
+	var last *ast.GenDecl
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			last = gen
+		}
+	}
+	return last
+}
+
+// isStdImport reports whether spec imports a standard library package,
+// whose path has no dot in its first element.
+func isStdImport(spec *ast.ImportSpec) bool {
+	p, err := strconv.Unquote(spec.Path.Value)
+	first, _, _ := strings.Cut(p, "/")
+	return err == nil && !strings.Contains(first, ".")
+}
+
+// guessPackageName returns the likely name of the package at importPath: its
+// last element, skipping a major version suffix such as "/v2" and cut at the
+// first dot or dash, as in "gopkg.in/redact.v2" or "go-redact".
+func guessPackageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexAny(name, ".-"); i > 0 {
+		name = name[:i]
+	}
+	if !token.IsIdentifier(name) {
+		return "redact"
+	}
+	return name
+}
@@ -0,0 +1,26 @@
+package analyzer
+
+import (
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/Wladim1r/loglinter/internal/rules"
+)
+
+// checkSpelling runs the spelling rule over each constant part of the
+// message and reports each misspelled word where it is written. Words in
+// string literals get the fix of the message when they have a single close
+// match.
+func checkSpelling(pass *analysis.Pass, sp *rules.Spelling, lc logCall, fix *messageFix) {
+	for _, part := range lc.parts {
+		if !part.constant {
+			continue
+		}
+		for _, issue := range sp.Check(part.value) {
+			d := analysis.Diagnostic{Message: issue.Message}
+			pos, end := partRange(part, issue.Offset, issue.Offset+len(issue.Word))
+			lc.pointAt(&d, pos, end)
+			d.SuggestedFixes = fix.suggestAt(part, issue.Fixed != "")
+			reportDiagnostic(pass, d)
+		}
+	}
+}
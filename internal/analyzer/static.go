@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/Wladim1r/loglinter/internal/config"
+	"github.com/Wladim1r/loglinter/internal/rules"
+)
+
+// checkStatic reports slog and *zap.Logger calls whose message is not a
+// constant: concatenations with variables, fmt.Sprintf results and plain
+// variables. Constant messages can be grouped, counted and alerted on; the
+// dynamic data belongs in attributes.
+//
+// For concatenations and fmt.Sprintf calls with a constant format, the fix
+// of the message ends by keeping its constant text as the message and moving
+// every operand into an attribute keyed by its identifier:
+//
+//	slog.Info("user " + id + " logged in")  →  slog.Info("user logged in", "id", id)
+func checkStatic(pass *analysis.Pass, lc logCall, fix *messageFix) {
+	if _, ok := staticAttr(pass, lc); !ok || !hasDynamicPart(lc.parts) {
+		return
+	}
+	reportDiagnostic(pass, analysis.Diagnostic{
+		Pos:            lc.msgArg.Pos(),
+		End:            lc.msgArg.End(),
+		Message:        "log message is not constant; use a static message and pass dynamic values as attributes",
+		SuggestedFixes: fix.suggest(config.RuleStatic),
+	})
+}
+
+// staticAttr reports whether the call is made through a structured logger,
+// slog or *zap.Logger, which has attributes to move the dynamic data into,
+// and returns the function formatting an attribute in its style. The
+// function is nil when the file does not import zap for zap.Any.
+func staticAttr(pass *analysis.Pass, lc logCall) (func(key, value string) string, bool) {
+	sel, ok := lc.call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	switch {
+	case pkgPathOf(pass.TypesInfo.Uses[sel.Sel]) == "log/slog":
+		return func(key, value string) string {
+			return fmt.Sprintf("%q, %s", key, value)
+		}, true
+	case receiverTypeName(pass, sel) == "go.uber.org/zap.Logger":
+		zapName := importName(lc.stack, "go.uber.org/zap")
+		if zapName == "" {
+			return nil, true
+		}
+		return func(key, value string) string {
+			return fmt.Sprintf("%s.Any(%q, %s)", zapName, key, value)
+		}, true
+	}
+	return nil, false
+}
+
+// staticRewrite returns the constant text of the message followed by its
+// dynamic operands as attributes, the source that replaces the message
+// argument. value renders an operand. It fails when an operand has no name
+// to derive a key from, or when nothing constant is left for the message.
+func staticRewrite(pass *analysis.Pass, parts []msgPart, attr func(key, value string) string, value func(ast.Expr) string) (string, bool) {
+	fragments, values, ok := dynamicOperands(pass, parts)
+	if !ok {
+		return "", false
+	}
+	msg := rules.StaticMessage(fragments)
+	if msg == "" {
+		return "", false
+	}
+
+	text := []string{strconv.Quote(msg)}
+	seen := make(map[string]int, len(values))
+	for _, v := range values {
+		key := attrKeyOf(v)
+		if key == "" {
+			return "", false
+		}
+		// Keys must stay unique within the entry: id, id_2, id_3...
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s_%d", key, n)
+		}
+		text = append(text, attr(key, value(v)))
+	}
+	return strings.Join(text, ", "), true
+}
+
+// dynamicOperands splits a message into the constant text around its dynamic
+// operands and the operands themselves, so that len(fragments) ==
+// len(values)+1. It understands concatenations and a single fmt.Sprintf call
+// with a constant format string.
+func dynamicOperands(pass *analysis.Pass, parts []msgPart) (fragments []string, values []ast.Expr, ok bool) {
+	if len(parts) == 1 {
+		return sprintfOperands(pass, parts[0].expr)
+	}
+
+	var cur strings.Builder
+	for _, p := range parts {
+		if p.constant {
+			cur.WriteString(p.value)
+			continue
+		}
+		fragments = append(fragments, cur.String())
+		cur.Reset()
+		values = append(values, p.expr)
+	}
+	return append(fragments, cur.String()), values, true
+}
+
+// sprintfOperands is dynamicOperands for a fmt.Sprintf call.
+func sprintfOperands(pass *analysis.Pass, expr ast.Expr) (fragments []string, values []ast.Expr, ok bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+		return nil, nil, false
+	}
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "fmt" || fn.Name() != "Sprintf" {
+		return nil, nil, false
+	}
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return nil, nil, false
+	}
+	fragments, verbs, ok := rules.SplitFormat(constant.StringVal(tv.Value))
+	if !ok || verbs != len(call.Args)-1 {
+		return nil, nil, false
+	}
+	return fragments, call.Args[1:], true
+}
+
+// attrKeyOf derives an attribute key from an operand: the name of a variable
+// or field, or of a method called without arguments. String() and Error()
+// calls are named after their receiver, so err.Error() becomes "err".
+func attrKeyOf(e ast.Expr) string {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		return rules.AttrKey(e.Name)
+	case *ast.SelectorExpr:
+		return rules.AttrKey(e.Sel.Name)
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok || len(e.Args) != 0 {
+			return ""
+		}
+		if sel.Sel.Name == "String" || sel.Sel.Name == "Error" {
+			return attrKeyOf(sel.X)
+		}
+		return rules.AttrKey(sel.Sel.Name)
+	}
+	return ""
+}
@@ -0,0 +1,6 @@
+// Package redact is a stub of a project's redaction helpers for analysistest
+// fixtures.
+package redact
+
+// String hides a sensitive value.
+func String(s string) string { return "[REDACTED]" }
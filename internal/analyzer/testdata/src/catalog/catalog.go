@@ -0,0 +1,23 @@
+package catalog
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+type server struct{ logger *zap.Logger }
+
+func (s *server) handle(id string, n int, err error) {
+	slog.Info("request handled", "id", id, slog.Int("n", n))
+	slog.Warn(fmt.Sprintf("retry %d of %s", n, id))
+	s.logger.Error("query failed", zap.String("id", id), zap.Error(err))
+	s.logger.Sugar().Info("user ", id)
+	log.Printf("cache size %d", n)
+}
+
+func start() {
+	slog.Debug("server " + "started")
+}
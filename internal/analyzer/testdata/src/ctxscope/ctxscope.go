@@ -0,0 +1,31 @@
+package ctxscope
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+func handle(ctx context.Context, logger *slog.Logger) {
+	slog.Info("handling request")       // want `slog.Info called while ctx is in scope; use InfoContext\(ctx, ...\)`
+	logger.Warn("slow request", "n", 1) // want `logger.Warn called while ctx is in scope`
+
+	go func() {
+		slog.Error("background job failed") // want `slog.Error called while ctx is in scope`
+	}()
+
+	// Already context-aware: the message follows the context.
+	slog.InfoContext(ctx, "handling request")
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("serving") // want `slog.Debug called while r.Context\(\) is in scope`
+}
+
+func noContext(_ context.Context) {
+	slog.Info("nothing to attach")
+}
+
+func plain() {
+	slog.Info("starting")
+}
@@ -0,0 +1,10 @@
+package discover
+
+import "log/slog"
+
+func run() {
+	// lowercase is disabled by the .loglinter.yaml next to this file.
+	slog.Info("Starting server")
+
+	slog.Warn("connection failed!") // want `log message contains forbidden special character '!'`
+}
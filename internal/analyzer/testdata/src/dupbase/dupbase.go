@@ -0,0 +1,9 @@
+package dupbase // want package:"3 log calls"
+
+import "log/slog"
+
+func Fetch() {
+	slog.Error("request failed")
+	slog.Info("cache warmed")
+	slog.Info("operation completed")
+}
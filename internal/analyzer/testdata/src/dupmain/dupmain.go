@@ -0,0 +1,23 @@
+package dupmain // want package:"6 log calls"
+
+import (
+	"log/slog"
+
+	"dupbase"
+)
+
+func handle(id string) {
+	dupbase.Fetch()
+	slog.Error("request failed") // want `log message "request failed" is used at 3 call sites \(maximum 1\): dupbase/dupbase.go:6, dupmain/dupmain.go:11, dupmain/dupmain.go:20; make each message specific`
+	slog.Info("cache warmed")    // want `log message "cache warmed" is used at 2 call sites`
+
+	// Fine.
+	slog.Info("operation completed")
+	slog.Info("user " + id + " loaded")
+}
+
+func retry() {
+	slog.Error("request failed") // want `log message "request failed" is used at 3 call sites`
+	slog.Info("operation completed")
+	slog.Info("user " + "x" + " loaded")
+}
@@ -0,0 +1,10 @@
+package dupsibling // want package:"2 log calls"
+
+import "log/slog"
+
+// dupsibling shares messages with dupbase and dupmain but does not import
+// them, so its call sites are not compared with theirs.
+func Fetch() {
+	slog.Error("request failed")
+	slog.Info("cache warmed")
+}
@@ -0,0 +1,23 @@
+package english
+
+import (
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+const greeting = "привет"
+
+func run(logger *zap.Logger, id string) {
+	slog.Info("ошибка подключения")                  // want `log message contains non-English characters \(Cyrillic script, rune 'о' at column 1\)`
+	slog.Info("café " + id + " Щит готов")           // want `non-English characters \(Cyrillic script, rune 'Щ' at column 2\)`
+	logger.Warn(`ЩИТ down`)                          // want `non-English characters \(Cyrillic script, rune 'Щ' at column 1\)`
+	slog.Info("network σφάλμα σύνδεσης")             // want `non-English characters \(Greek script, rune 'σ' at column 9\)`
+	slog.Info("retry… Übertragung in Zürich failed") // ok: accented Latin is allowed by default
+
+	// No fix: a named constant, untransliterable scripts, or an empty result.
+	slog.Info(greeting + " world") // want `non-English characters \(Cyrillic script, rune 'п' at column 1\)`
+	slog.Info("连接失败")              // want `non-English characters \(Han script, rune '连' at column 1\)`
+	slog.Info("ъ")                 // want `non-English characters \(Cyrillic script, rune 'ъ' at column 1\)`
+	slog.Info("deployed 🚀")        // want `non-English characters \(Common script, rune '🚀' at column 10\)`
+}
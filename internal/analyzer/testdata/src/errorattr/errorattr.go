@@ -0,0 +1,43 @@
+package errorattr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+func query() error { return errors.New("boom") }
+
+func handle(logger *zap.Logger) {
+	err := query()
+	if err != nil {
+		slog.Error("db query failed")             // want `Error-level log call inside .if err != nil. does not include err`
+		slog.Warn("retrying query", "attempt", 2) // want `Warn-level log call inside .if err != nil. does not include err`
+		logger.Error("db query failed")           // want `Error-level log call inside .if err != nil. does not include err`
+		logger.Sugar().Error("db query failed")   // want `Error-level log call inside .if err != nil. does not include err`
+		slog.Info("falling back to cache")
+		return
+	}
+
+	if err := query(); err != nil && !errors.Is(err, io.EOF) {
+		slog.Error("read failed") // want `Error-level log call inside .if err != nil. does not include err`
+	}
+
+	// The error is attached.
+	if err := query(); err != nil {
+		slog.Error("db query failed", "err", err)
+		slog.Error("db query failed", slog.Any("error", err))
+		slog.Error(fmt.Sprintf("db query failed: %v", err))
+		logger.Error("db query failed", zap.Error(err))
+		logger.Sugar().Error("db query failed: ", err)
+	}
+
+	// Not inside an error branch.
+	slog.Error("unexpected state")
+	if err == nil {
+		slog.Error("no error but nothing found")
+	}
+}
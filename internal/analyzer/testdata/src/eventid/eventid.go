@@ -0,0 +1,30 @@
+package eventid // want package:"13 log calls"
+
+import (
+	"log/slog"
+
+	"eventidbase"
+
+	"go.uber.org/zap"
+)
+
+func run(logger *zap.Logger, id string, err error, args []any) {
+	eventidbase.Fetch()
+
+	slog.Error("request failed", "err", err)     // want `error-level log call has no "event_id" attribute; add a stable event ID`
+	slog.Warn("Cache miss for user", "user", id) // want `warn-level log call has no "event_id" attribute`
+	slog.Error("request failed", "err", err)     // want `error-level log call has no "event_id" attribute`
+	logger.Error("query failed", zap.Error(err)) // want `error-level log call has no "event_id" attribute`
+	slog.Error("request failed", args...)        // want `error-level log call has no "event_id" attribute`
+
+	slog.Error("payment declined", "event_id", id)                           // want `value of "event_id" must be a constant string`
+	slog.Error("payment declined", "event_id", "Payment Declined")           // want `event ID "Payment Declined" does not match the pattern`
+	slog.Error("order timed out", slog.String("event_id", "orders.timeout")) // want `event ID "orders.timeout" is used at 2 call sites: eventid/eventid.go:22, eventidbase/eventidbase.go:6`
+	slog.Error("upstream gone", "event_id", "eventid.upstream_gone")         // want `event ID "eventid.upstream_gone" is used at 2 call sites`
+	slog.Error("upstream unavailable", "event_id", "eventid.upstream_gone")  // want `event ID "eventid.upstream_gone" is used at 2 call sites`
+
+	// Fine.
+	slog.Info("server started")
+	slog.Error("order failed", "event_id", "orders.failed")
+	logger.Warn("disk almost full", zap.String("event_id", "storage.disk_full"))
+}
@@ -0,0 +1,8 @@
+package eventidbase
+
+import "log/slog"
+
+func Fetch() {
+	slog.Error("order timed out", "event_id", "orders.timeout")
+	slog.Error("query failed", "event_id", "eventid.query_failed")
+}
@@ -0,0 +1,21 @@
+package fatalallowed
+
+import (
+	"log"
+	"os"
+)
+
+func mustOpen(path string) *os.File {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("cannot open %s", path)
+	}
+	return f
+}
+
+func process(path string) {
+	f := mustOpen(path)
+	defer f.Close()
+	log.Panic("unsupported format")
+	log.Fatal("cannot continue") // want `log.Fatal after defer exits the process without running the deferred calls`
+}
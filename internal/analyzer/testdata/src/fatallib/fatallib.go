@@ -0,0 +1,48 @@
+package fatallib
+
+import (
+	"log"
+	"os"
+)
+
+func init() {
+	if os.Getenv("HOME") == "" {
+		log.Fatal("home is not set")
+	}
+}
+
+func load(path string, logger *log.Logger) {
+	if path == "" {
+		log.Fatal("empty path") // want `log.Fatal in library package fatallib terminates the host process`
+	}
+	logger.Panicf("cannot load %s", path) // want `logger.Panicf in library package fatallib terminates the host process`
+	log.Println("loaded")
+}
+
+func cleanup(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	log.Fatalln("giving up") // want `log.Fatalln after defer exits the process without running the deferred calls`
+}
+
+func run(path string) {
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+	} else {
+		// The defer above is in a sibling branch and never ran on this path.
+		log.Fatal("no path given") // want `log.Fatal in library package fatallib terminates the host process`
+	}
+	switch {
+	case path == "-":
+		defer log.Println("done")
+	default:
+		log.Panic("unsupported path") // want `log.Panic in library package fatallib terminates the host process`
+	}
+}
@@ -0,0 +1,23 @@
+package fatallib
+
+import (
+	"log"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("FATALLIB_SKIP") != "" {
+		log.Fatal("skipping tests")
+	}
+	os.Exit(m.Run())
+}
+
+func fixture(t *testing.T, path string) *os.File {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("open fixture: %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: fatalmain FILE")
+	}
+	go func() {
+		log.Fatalf("worker failed") // want `log.Fatalf inside a goroutine exits the whole process`
+	}()
+
+	f, err := os.Open(os.Args[1])
+	if err != nil {
+		log.Fatal("cannot open file")
+	}
+	defer f.Close()
+
+	log.Panic("unexpected state")
+	log.Fatal("cannot continue") // want `log.Fatal after defer exits the process without running the deferred calls`
+}
@@ -0,0 +1,21 @@
+package fixes
+
+import "log/slog"
+
+const server = "Server "
+
+func run(key string) {
+	// Every diagnostic of a call offers the same fix, which rewrites all
+	// literals of the message at once.
+	slog.Info("Ошибка подключения 🚀")                   // want `lowercase letter` `non-English characters` `emoji`
+	slog.Info("Cache miss for " + key + ", retrying!!") // want `lowercase letter` `forbidden special character '!'`
+	slog.Info("  Failed to recieve  payment.")          // want `lowercase letter` `start with whitespace` `end with a period` `repeated spaces` `misspelled word "recieve"`
+	slog.Info(`Request failed.`)                        // want `lowercase letter` `end with a period`
+	slog.Info("Line\tbroken " + key)                    // want `lowercase letter` `control character`
+	slog.Info("Ждём " + key + " ответа")                // want `lowercase letter` `non-English characters`
+
+	// Only the fixable part is fixed: the first letter is in a constant and
+	// Han has no transliteration.
+	slog.Info(server + "ready ✅") // want `lowercase letter` `non-English characters` `emoji`
+	slog.Info("Starting 服务")      // want `lowercase letter` `non-English characters`
+}
@@ -0,0 +1,22 @@
+package format
+
+import "log/slog"
+
+const suffix = " done."
+
+func run(id string) {
+	slog.Info("server started.")            // want `log message should not end with a period`
+	slog.Info(" server started")            // want `log message should not start with whitespace`
+	slog.Info("server started ")            // want `log message should not end with whitespace`
+	slog.Info("first line\nsecond line")    // want `log message contains control character '\\n'`
+	slog.Info(`raw	tab`)                    // want `log message contains control character '\\t'`
+	slog.Info("cache  miss")                // want `log message contains repeated spaces`
+	slog.Info("user " + id + " logged in.") // want `log message should not end with a period`
+	slog.Info("user  " + id + " logged in") // want `log message contains repeated spaces`
+	slog.Info("migration" + suffix)         // want `log message should not end with a period`
+
+	// Fine.
+	slog.Info("user " + id + " logged in")
+	slog.Info("failed: " + id)
+	slog.Info("waiting...")
+}
@@ -0,0 +1,45 @@
+// Package zap is a minimal stub of go.uber.org/zap for analysistest fixtures.
+package zap
+
+// Field is a typed log attribute.
+type Field struct {
+	Key    string
+	String string
+	Iface  interface{}
+}
+
+// String constructs a field with a string value.
+func String(key, val string) Field { return Field{Key: key, String: val} }
+
+// Any constructs a field with an arbitrary value.
+func Any(key string, val interface{}) Field { return Field{Key: key, Iface: val} }
+
+// Error constructs a field that carries err under the "error" key.
+func Error(err error) Field { return Field{Key: "error", Iface: err} }
+
+// Logger is a structured logger.
+type Logger struct{}
+
+// NewNop returns a no-op Logger.
+func NewNop() *Logger { return &Logger{} }
+
+func (l *Logger) Debug(msg string, fields ...Field)  {}
+func (l *Logger) Info(msg string, fields ...Field)   {}
+func (l *Logger) Warn(msg string, fields ...Field)   {}
+func (l *Logger) Error(msg string, fields ...Field)  {}
+func (l *Logger) DPanic(msg string, fields ...Field) {}
+func (l *Logger) Panic(msg string, fields ...Field)  {}
+func (l *Logger) Fatal(msg string, fields ...Field)  {}
+
+// Sugar wraps the Logger in a SugaredLogger.
+func (l *Logger) Sugar() *SugaredLogger { return &SugaredLogger{} }
+
+// SugaredLogger is a loosely typed logger.
+type SugaredLogger struct{}
+
+func (s *SugaredLogger) Debug(args ...interface{}) {}
+func (s *SugaredLogger) Info(args ...interface{})  {}
+func (s *SugaredLogger) Warn(args ...interface{})  {}
+func (s *SugaredLogger) Error(args ...interface{}) {}
+func (s *SugaredLogger) Panic(args ...interface{}) {}
+func (s *SugaredLogger) Fatal(args ...interface{}) {}
@@ -0,0 +1,84 @@
+package httpvalues
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+func fetch(client *http.Client, req *http.Request) {
+	resp, err := client.Do(req)
+	if err != nil {
+		slog.Error("call failed", "err", err) // want `log argument err is an error from net/http or net/url`
+		return
+	}
+	defer resp.Body.Close()
+
+	slog.Info("request done", "req", req)                  // want `log argument req is a \*http.Request .*log req.Method and req.URL.Redacted\(\) instead`
+	slog.Info("response received", slog.Any("resp", resp)) // want `log argument resp is a \*http.Response`
+	slog.Debug("response headers", "headers", resp.Header) // want `log argument resp.Header is a http.Header`
+	slog.Info("calling " + fmt.Sprintf("%v", req.URL))     // want `log argument req.URL is a url.URL .*log req.URL.Redacted\(\) instead`
+	log.Printf("request %v failed", req)                   // want `log argument req is a \*http.Request`
+
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		slog.Warn("url error", "err", uerr) // want `log argument uerr is a \*url.Error`
+	}
+
+	// Redacted accessors are fine.
+	slog.Info("request done", "method", req.Method, "url", req.URL.Redacted())
+	slog.Info("response received", "status", resp.StatusCode)
+}
+
+func parse(raw string) {
+	if _, err := url.Parse(raw); err != nil {
+		slog.Error("invalid url", "err", err) // want `log argument err is an error from net/http or net/url`
+	}
+
+	// Errors from other packages are fine.
+	err := errors.New("boom")
+	slog.Error("failed", "err", err)
+}
+
+// load shares err between calls: uses that a net/http or net/url assignment
+// may reach, in an enclosing block or in a branch since the last assignment
+// that always runs, are reported.
+func load(client *http.Client, req *http.Request, path string) error {
+	_, err := client.Do(req)
+	if err != nil {
+		slog.Warn("fetch failed", "err", err) // want `log argument err is an error from net/http or net/url`
+	}
+
+	err = readFile(path)
+	if err != nil {
+		slog.Error("read failed", "err", err)
+	}
+
+	if path == "" {
+		_, err = url.Parse(path)
+	}
+	slog.Error("parse skipped", "err", err) // want `log argument err is an error from net/http or net/url`
+
+	err = readFile(path)
+	switch path {
+	case "":
+		err = errors.New("empty path")
+	case "-":
+		_, err = client.Do(req)
+	}
+	slog.Error("load failed", "err", err) // want `log argument err is an error from net/http or net/url`
+
+	err = readFile(path)
+	slog.Error("read failed", "err", err)
+
+	var uerr error
+	if _, uerr = url.Parse(path); uerr != nil {
+		slog.Error("parse failed", "err", uerr) // want `log argument uerr is an error from net/http or net/url`
+	}
+	return err
+}
+
+func readFile(string) error { return nil }
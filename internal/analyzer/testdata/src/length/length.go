@@ -0,0 +1,14 @@
+package length
+
+import "log/slog"
+
+func run(payload, msg string) {
+	slog.Info("")                                                                               // want `log message is empty`
+	slog.Info(`{"event":"order_created","order_id":12345,"items":[{"sku":"A-1","qty":2}]}`)     // want `log message is too long \(74 runes, maximum 40\); keep the message short and move data into structured attributes`
+	slog.Info("payload received: " + payload + ", processing it now with the default pipeline") // want `log message is too long`
+
+	// Fine.
+	slog.Info("order created", "payload", payload)
+	slog.Info(msg)
+	slog.Info("" + msg)
+}
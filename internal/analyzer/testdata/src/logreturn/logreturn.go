@@ -0,0 +1,60 @@
+package logreturn
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+func query() (int, error) { return 0, errors.New("boom") }
+
+func load() (int, error) {
+	n, err := query()
+	if err != nil {
+		slog.Error("query failed", "err", err) // want `err is logged and then returned unwrapped, so callers will log it again`
+		return 0, err
+	}
+
+	switch n {
+	case 0:
+		if _, err := query(); err != nil {
+			slog.Warn("retry failed", slog.Any("error", err)) // want `err is logged and then returned unwrapped`
+			return 0, (err)
+		}
+	}
+
+	// Wrapped errors carry context for the caller to log.
+	if _, err := query(); err != nil {
+		slog.Error("query failed", "err", err)
+		return 0, fmt.Errorf("load: %w", err)
+	}
+
+	// Handled here: logged but not returned.
+	if _, err := query(); err != nil {
+		slog.Warn("using cached value", "err", err)
+		return n, nil
+	}
+
+	// Reassigned before the return: the caller gets a different error.
+	if _, err := query(); err != nil {
+		slog.Error("query failed", "err", err)
+		err = fmt.Errorf("load: %w", err)
+		return 0, err
+	}
+
+	// Reassigned in a nested block counts too.
+	if _, err := query(); err != nil {
+		slog.Error("query failed", "err", err)
+		if n > 0 {
+			err = fmt.Errorf("load %d: %w", n, err)
+		}
+		return 0, err
+	}
+
+	// Returned but not logged.
+	if _, err := query(); err != nil {
+		slog.Info("query failed")
+		return 0, err
+	}
+	return n, nil
+}
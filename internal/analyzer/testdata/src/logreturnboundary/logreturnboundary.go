@@ -0,0 +1,14 @@
+package logreturnboundary
+
+import (
+	"errors"
+	"log/slog"
+)
+
+func serve() error {
+	if err := errors.New("boom"); err != nil {
+		slog.Error("request failed", "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package pii
+
+import (
+	"context"
+	"log/slog"
+)
+
+const supportEmail = "support@example.com"
+
+func run(addr string) {
+	slog.Info("contact admin@example.com for access")  // want `personal data \(email address "admin@example.com"\)`
+	slog.Info("contact " + supportEmail)               // want `personal data \(email address "support@example.com"\)`
+	slog.Info("charging card 4111 1111 1111 1111")     // want `personal data \(payment card number`
+	slog.Info("payout to DE89 3704 0044 0532 0130 00") // want `personal data \(IBAN`
+	slog.Info("calling +1 415 555 2671")               // want `personal data \(phone number`
+	slog.Info("peer 2001:db8::1 connected")            // want `personal data \(IPv6 address`
+
+	// Constant key/value arguments and attribute constructors are checked too.
+	slog.Info("connecting", "addr", "198.51.100.3")           // want `personal data \(IPv4 address "198.51.100.3"\)`
+	slog.Info("user created", slog.String("email", "a@b.io")) // want `personal data \(email address "a@b.io"\)`
+
+	// Not personal data.
+	slog.Info("listening on 127.0.0.1:8080")
+	slog.Info("upgraded to 1.2.3.4.5")
+	slog.Info("version 1.2.3.4 released")
+	slog.Info("cache::key not found")
+	slog.Info("order 1234567890123 shipped")
+	slog.Info("card 4111 1111 1111 1112 rejected")
+	slog.Info("started at 12:30:45")
+	slog.Info("connecting", "addr", addr)
+}
+
+func runContext(ctx context.Context) {
+	// The message of the context variants follows the context argument.
+	slog.InfoContext(ctx, "contact admin@example.com") // want `personal data \(email address "admin@example.com"\)`
+}
@@ -0,0 +1,22 @@
+package ranges
+
+import "log/slog"
+
+const (
+	greeting = "hello "
+	ready    = "ready!"
+)
+
+func run(password string) {
+	slog.Info("  Starting server")                        // want `lowercase letter`
+	slog.Info("cache is " + "ready!")                     // want `forbidden special character '!'`
+	slog.Info("cache \"warm\"")                           // want `forbidden special character '"'`
+	slog.Info(`disk #2 full`)                             // want `forbidden special character '#'`
+	slog.Info("retry " + "\u043eк")                       // want `non-English characters`
+	slog.Info(greeting + "δ")                             // want `non-English characters`
+	slog.Info("user logged in " + password)               // want `sensitive`
+	slog.Info("refreshing token")                         // want `sensitive`
+	slog.Info("user created", "contact", "a@example.com") // want `personal data \(email address "a@example.com"\)`
+	slog.Info(greeting + "peer 198.51.100.3")             // want `personal data \(IPv4 address "198.51.100.3"\)`
+	slog.Info("cache is " + ready)                        // want `forbidden special character '!'`
+}
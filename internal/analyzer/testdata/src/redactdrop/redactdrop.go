@@ -0,0 +1,19 @@
+package redactdrop
+
+import "log/slog"
+
+func login(user, password, token string) {
+	slog.Info("login", "password", password)                                  // want `log attribute "password" may contain sensitive data \(keyword "password"\)`
+	slog.Info("session refreshed", slog.String("token", token), "user", user) // want `log attribute "token" may contain sensitive data \(keyword "token"\)`
+	slog.Info("login ok, token: " + token)                                    // want `keyword "token" found in message text`
+	slog.Info("user " + user + " token=" + token + " issued")                 // want `keyword "token" found in message text`
+	slog.Info("login rejected: " + password)                                  // want `keyword "password" found in argument expression`
+	slog.Info("token: " + token)                                              // want `keyword "token" found in message text`
+	slog.Info(password + " - rejected")                                       // want `keyword "password" found in argument expression`
+
+	// No value to drop: the keyword is part of the message, the value is
+	// the whole message, or dropping it would leave a dangling word.
+	slog.Info("refreshing token")                   // want `keyword "token" found in message text`
+	slog.Info(token)                                // want `keyword "token" found in argument expression`
+	slog.Info("user " + user + " with " + password) // want `keyword "password" found in argument expression`
+}
@@ -0,0 +1,11 @@
+package redactfixes
+
+import "log/slog"
+
+// The sensitive rule shares the fix of the message with the rules that
+// rewrite its text, so the diagnostics of a call offer the same edits.
+func login(user, token string) {
+	slog.Info("login ok, token=" + token)                   // want `forbidden special character '='` `keyword "token" found in message text`
+	slog.Info("Token: " + token)                            // want `should start with a lowercase letter` `keyword "token" found in message text`
+	slog.Info("Login OK! token: " + token + " for " + user) // want `should start with a lowercase letter` `forbidden special character '!'` `keyword "token" found in message text`
+}
@@ -0,0 +1,9 @@
+package redactfixeswrap
+
+import "log/slog"
+
+// Wrapping an operand leaves the literals to the other rules of the fix.
+func login(token string) {
+	slog.Info("Login ok, token=" + token)           // want `should start with a lowercase letter` `forbidden special character '='` `keyword "token" found in message text`
+	slog.Info("Session refreshed! token: " + token) // want `should start with a lowercase letter` `forbidden special character '!'` `keyword "token" found in message text`
+}
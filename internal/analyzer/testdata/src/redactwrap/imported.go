@@ -0,0 +1,21 @@
+package redactwrap
+
+import (
+	"log/slog"
+
+	r "acme/redact"
+)
+
+func refresh(token string) {
+	slog.Info("token: "+token, "token", token) // want `keyword "token" found in message text` `log attribute "token"`
+
+	// Redacted values are fine.
+	slog.Info("token: " + r.String(token))
+	slog.Info("refreshed", "token", r.String(token))
+}
+
+func shadowedImport(token string) {
+	// The existing import is shadowed here: no fix.
+	r := "on"
+	slog.Info("token: "+token, "r", r) // want `keyword "token" found in message text`
+}
@@ -0,0 +1,21 @@
+package redactwrap
+
+import (
+	"log/slog"
+)
+
+func login(user, password, token string, secretKey []byte) {
+	slog.Info("login", "password", password)                    // want `log attribute "password" may contain sensitive data`
+	slog.Info("session refreshed", slog.String("token", token)) // want `log attribute "token" may contain sensitive data`
+	slog.Info("user " + user + " token: " + token)              // want `keyword "token" found in message text`
+
+	// The helper takes a string: other values are reported without a fix.
+	slog.Info("key loaded", "key", secretKey) // want `log attribute "key" may contain sensitive data \(keyword "secret"\)`
+}
+
+// The package name is taken in shadowed, so every fix in the file uses one
+// alias and the fixes add a single import.
+func shadowed(token string) {
+	redact := "on"
+	slog.Info("token: "+token, "redact", redact) // want `keyword "token" found in message text`
+}
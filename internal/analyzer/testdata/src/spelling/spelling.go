@@ -0,0 +1,23 @@
+package spelling
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+const msgPrefix = "paymnet "
+
+func run(id string, n int) {
+	slog.Info("failed to recieve payment")    // want `log message contains misspelled word "recieve" \(did you mean "receive"\?\)`
+	slog.Info("conenction closed", "id", id)  // want `misspelled word "conenction" \(did you mean "connection"\?\)`
+	slog.Info("order " + id + " was cancled") // want `misspelled word "cancled" \(did you mean "canceled"\?\)`
+	slog.Info(`subscripton renewed`)          // want `misspelled word "subscripton" \(did you mean "subscription"\?\)`
+	slog.Info("job stoped")                   // want `misspelled word "stoped" \(did you mean one of "stopped", "stomped", "stored"\?\)`
+	slog.Info(msgPrefix + "declined")         // want `misspelled word "paymnet"`
+	slog.Info(fmt.Sprintf("retry %d of %s", n, id))
+
+	// Fine.
+	slog.Info("payment declined by the issuer")
+	slog.Info("acmepay charge created")
+	slog.Info("userID lookup_count /var/lib/queue https://exmaple.com")
+}
@@ -0,0 +1,33 @@
+package static
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+type user struct{ ID int }
+
+func run(logger *zap.Logger, u user, id, userID, addr string, attempts int, err error, msg string, args []any) {
+	slog.Info("user " + id + " logged in")                                  // want `log message is not constant`
+	slog.Warn(fmt.Sprintf("attempt %d to reach %s failed", attempts, addr)) // want `log message is not constant`
+	slog.Info("login user=" + userID + ", addr=" + addr)                    // want `log message is not constant`
+	slog.Error(fmt.Sprintf("query failed: %v", err.Error()))                // want `log message is not constant`
+	slog.Error("copy " + id + " to " + u.Name())                            // want `log message is not constant`
+	slog.Error("copy " + id + " over " + id)                                // want `log message is not constant`
+	logger.Info("user " + id + " created")                                  // want `log message is not constant`
+	slog.Info("loaded user " + fmt.Sprint(u.ID))                            // want `log message is not constant`
+	slog.Info(msg)                                                          // want `log message is not constant`
+	slog.Info("user "+id, args...)                                          // want `log message is not constant`
+
+	// Fine.
+	slog.Info("user logged in", "id", id)
+	slog.Info("user" + " logged in")
+	logger.Info("user created", zap.String("id", id))
+	log.Printf("user %s logged in", id)
+	log.Print("user " + id + " logged in")
+}
+
+func (u user) Name() string { return "" }
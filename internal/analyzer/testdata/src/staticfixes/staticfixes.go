@@ -0,0 +1,16 @@
+package staticfixes
+
+import (
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+// The static rewrite is the last step of the fix of the message, so it
+// keeps the text the other rules cleaned and does not conflict with them.
+func run(logger *zap.Logger, id, user, token string) {
+	slog.Info("User " + id + " logged in.")                  // want `should start with a lowercase letter` `should not end with a period` `log message is not constant`
+	slog.Info("Payment " + id + " failed!!")                 // want `should start with a lowercase letter` `forbidden special character '!'` `log message is not constant`
+	slog.Info("User " + user + " logged in, token=" + token) // want `should start with a lowercase letter` `forbidden special character '='` `keyword "token" found in message text` `log message is not constant`
+	logger.Info("User " + id + " created!")                  // want `should start with a lowercase letter` `forbidden special character '!'` `log message is not constant`
+}
@@ -0,0 +1,13 @@
+package zapfields
+
+import "go.uber.org/zap"
+
+// zap.Error builds a field; it is not an Error-level log call whose message
+// is its argument. Only the attribute it builds is checked.
+func run(logger *zap.Logger, err, tokenErr error) {
+	logger.Info("user loaded", zap.String("status", "ready"), zap.Error(err))
+	logger.Warn("refresh failed", zap.Error(tokenErr)) // want `log attribute "error" may contain sensitive data \(keyword "token"\)`
+
+	// The Logger methods are log calls.
+	logger.Error("Query failed", zap.Error(err)) // want `log message should start with a lowercase letter`
+}
@@ -0,0 +1,84 @@
+package catalog_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Wladim1r/loglinter/internal/catalog"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+	entries := []catalog.Entry{
+		{Message: "request handled", Level: "info", Logger: "slog", Keys: []string{"id"}, Package: "app", Function: "Handle", File: "app/app.go", Line: 12},
+		{Message: "retry {n}", Dynamic: true, Level: "warn", Logger: "zap", Package: "app", Function: "Retry", File: "app/app.go", Line: 30},
+	}
+
+	var buf bytes.Buffer
+	if err := catalog.Write(&buf, entries, catalog.FormatJSON); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := catalog.ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("round trip = %+v, want %+v", got, entries)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+	entries := []catalog.Entry{
+		{Message: "user, id", Level: "info", Logger: "slog", Keys: []string{"id", "n"}, Package: "app", Function: "Handle", File: "app/app.go", Line: 12},
+	}
+
+	var buf bytes.Buffer
+	if err := catalog.Write(&buf, entries, catalog.FormatCSV); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := "message,dynamic,level,logger,keys,package,function,file,line\n" +
+		"\"user, id\",false,info,slog,id n,app,Handle,app/app.go,12\n"
+	if buf.String() != want {
+		t.Errorf("CSV = %q, want %q", buf.String(), want)
+	}
+	if err := catalog.Write(&buf, entries, "xml"); err == nil {
+		t.Error("Write with an unknown format returned no error")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	entry := func(fn, msg, level string, line int) catalog.Entry {
+		return catalog.Entry{Message: msg, Level: level, Logger: "slog", Package: "app", Function: fn, File: "app/app.go", Line: line}
+	}
+	from := []catalog.Entry{
+		entry("Handle", "request handled", "info", 10),
+		entry("Handle", "request failed", "error", 12),
+		entry("Retry", "retrying", "warn", 20),
+		entry("Stop", "server stopped", "info", 30),
+	}
+	to := []catalog.Entry{
+		// Moved down by a new line: unchanged.
+		entry("Handle", "request handled", "info", 11),
+		entry("Handle", "request could not be decoded", "error", 13),
+		entry("Retry", "retrying", "info", 21),
+		entry("Start", "server started", "info", 25),
+	}
+
+	var buf bytes.Buffer
+	if err := catalog.WriteDiff(&buf, catalog.Diff(from, to)); err != nil {
+		t.Fatalf("WriteDiff: %v", err)
+	}
+	want := strings.Join([]string{
+		`~ app.Handle: error "request failed" -> error "request could not be decoded" (app/app.go:13)`,
+		`~ app.Retry: warn "retrying" -> info "retrying" (app/app.go:21)`,
+		`+ app.Start: info "server started" (app/app.go:25)`,
+		`- app.Stop: info "server stopped" (app/app.go:30)`,
+	}, "\n") + "\n"
+	if buf.String() != want {
+		t.Errorf("diff =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
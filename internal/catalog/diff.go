@@ -0,0 +1,154 @@
+package catalog
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Kinds of Change.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a difference between two catalogs. Old is nil for added entries
+// and New is nil for removed ones.
+type Change struct {
+	Kind string
+	Old  *Entry
+	New  *Entry
+}
+
+// Diff compares catalog from with the later catalog to. Line numbers shift
+// between releases, so entries are matched by package, function and message
+// instead: a matched entry whose level, logger or keys differ is changed.
+// Entries left over in the same function are paired in source order as
+// changed messages; the rest are added or removed.
+func Diff(from, to []Entry) []Change {
+	type key struct{ pkg, fn, msg string }
+	type scope struct{ pkg, fn string }
+
+	pending := make(map[key][]*Entry)
+	for i := range from {
+		e := &from[i]
+		k := key{e.Package, e.Function, e.Message}
+		pending[k] = append(pending[k], e)
+	}
+
+	var changes []Change
+	added := make(map[scope][]*Entry)
+	for i := range to {
+		e := &to[i]
+		k := key{e.Package, e.Function, e.Message}
+		if olds := pending[k]; len(olds) > 0 {
+			pending[k] = olds[1:]
+			if !sameEntry(olds[0], e) {
+				changes = append(changes, Change{Kind: Changed, Old: olds[0], New: e})
+			}
+			continue
+		}
+		s := scope{e.Package, e.Function}
+		added[s] = append(added[s], e)
+	}
+
+	removed := make(map[scope][]*Entry)
+	for i := range from {
+		e := &from[i]
+		k := key{e.Package, e.Function, e.Message}
+		if olds := pending[k]; len(olds) > 0 && olds[0] == e {
+			pending[k] = olds[1:]
+			s := scope{e.Package, e.Function}
+			removed[s] = append(removed[s], e)
+		}
+	}
+
+	for s, olds := range removed {
+		news := added[s]
+		n := min(len(olds), len(news))
+		for i := 0; i < n; i++ {
+			changes = append(changes, Change{Kind: Changed, Old: olds[i], New: news[i]})
+		}
+		for _, e := range olds[n:] {
+			changes = append(changes, Change{Kind: Removed, Old: e})
+		}
+		added[s] = news[n:]
+	}
+	for _, news := range added {
+		for _, e := range news {
+			changes = append(changes, Change{Kind: Added, New: e})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i].entry(), changes[j].entry()
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.Function != b.Function {
+			return a.Function < b.Function
+		}
+		return a.Line < b.Line
+	})
+	return changes
+}
+
+// entry returns the new entry of a change, or the old one when it was
+// removed.
+func (c Change) entry() *Entry {
+	if c.New != nil {
+		return c.New
+	}
+	return c.Old
+}
+
+// sameEntry reports whether two entries with the same message describe the
+// same statement, ignoring its location.
+func sameEntry(a, b *Entry) bool {
+	return a.Level == b.Level && a.Logger == b.Logger && a.Dynamic == b.Dynamic && slices.Equal(a.Keys, b.Keys)
+}
+
+// WriteDiff writes changes to w, one per line, as printed by the catalog
+// subcommand:
+//
+//	$ loglinter catalog -diff v1.json v2.json
+//	+ app/orders.Create: info "order created" (orders/create.go:42)
+//	- app/orders.Cancel: info "order cancelled" (orders/cancel.go:17)
+//	~ app/orders.Pay: error "payment failed" -> warn "payment declined" (orders/pay.go:30)
+func WriteDiff(w io.Writer, changes []Change) error {
+	for _, c := range changes {
+		e := c.entry()
+		var line string
+		switch c.Kind {
+		case Added:
+			line = fmt.Sprintf("+ %s: %s", scopeName(e), describe(c.New))
+		case Removed:
+			line = fmt.Sprintf("- %s: %s", scopeName(e), describe(c.Old))
+		default:
+			line = fmt.Sprintf("~ %s: %s -> %s", scopeName(e), describe(c.Old), describe(c.New))
+		}
+		if _, err := fmt.Fprintf(w, "%s (%s:%d)\n", line, e.File, e.Line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scopeName(e *Entry) string {
+	if e.Function == "" {
+		return e.Package
+	}
+	return e.Package + "." + e.Function
+}
+
+// describe formats the level, message and keys of an entry.
+func describe(e *Entry) string {
+	s := fmt.Sprintf("%s %q", e.Level, e.Message)
+	if len(e.Keys) > 0 {
+		s += " [" + strings.Join(e.Keys, " ") + "]"
+	}
+	return s
+}
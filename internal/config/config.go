@@ -4,10 +4,18 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/Wladim1r/loglinter/internal/rules"
 )
 
 // Rule names used as keys in the Disabled / Enabled maps.
@@ -16,8 +24,74 @@ const (
 	RuleEnglish   = "english"
 	RuleSpecial   = "special"
 	RuleSensitive = "sensitive"
+	RulePII       = "pii"
+	RuleSecrets   = "secrets"
+	RuleFatal     = "fatal"
+	// RuleHTTPValues is opt-in: it flags logging of net/http and net/url
+	// values whose text may contain full URLs, tokens or headers.
+	RuleHTTPValues = "httpvalues"
+	// RuleContext is opt-in: it asks for slog's *Context methods when a
+	// context is in scope.
+	RuleContext = "context"
+	// RuleErrorAttr is opt-in: it asks Error- and Warn-level calls made
+	// while handling an error to include that error.
+	RuleErrorAttr = "errorattr"
+	// RuleLogReturn is opt-in: it flags errors that are both logged and
+	// returned unwrapped.
+	RuleLogReturn = "logreturn"
+	// RuleFormat is opt-in: it checks trailing periods, whitespace, control
+	// characters and double spaces, see Config.Format.
+	RuleFormat = "format"
+	// RuleLength is opt-in: it bounds the length of the constant portion of
+	// a message, see Config.Length.
+	RuleLength = "length"
+	// RuleStatic is opt-in: it asks slog and zap.Logger messages to be
+	// constant, with dynamic data passed as attributes.
+	RuleStatic = "static"
+	// RuleDuplicate is opt-in: it flags constant messages shared by more
+	// call sites than allowed, see Config.Duplicate.
+	RuleDuplicate = "duplicate"
+	// RuleEventID is opt-in: it asks log calls of selected levels to carry
+	// a constant, unique event ID attribute, see Config.EventID.
+	RuleEventID = "eventid"
+	// RuleSpelling is opt-in: it checks the words of constant messages
+	// against an English dictionary, see Config.SpellingWords.
+	RuleSpelling = "spelling"
 )
 
+// defaultRules lists every rule known to loglinter together with its default
+// enabled state. Rule names that are not in this map are rejected by Load.
+var defaultRules = map[string]bool{
+	RuleLowercase: true,
+	RuleEnglish:   true,
+	RuleSpecial:   true,
+	RuleSensitive: true,
+	RulePII:       true,
+	RuleSecrets:   true,
+	RuleFatal:     true,
+
+	RuleHTTPValues: false,
+	RuleContext:    false,
+	RuleErrorAttr:  false,
+	RuleLogReturn:  false,
+	RuleFormat:     false,
+	RuleLength:     false,
+	RuleStatic:     false,
+	RuleDuplicate:  false,
+	RuleEventID:    false,
+	RuleSpelling:   false,
+}
+
+// RuleNames returns the names of all known rules in sorted order.
+func RuleNames() []string {
+	names := make([]string, 0, len(defaultRules))
+	for name := range defaultRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Config is the top-level configuration structure for loglinter.
 type Config struct {
 	// Rules allows selectively disabling individual rules.
@@ -28,13 +102,153 @@ type Config struct {
 
 	// SensitiveKeywords extends (or overrides) the default list of keywords
 	// that trigger the sensitive-data rule. Values are matched case-insensitively
-	// as substrings of the log message or concatenated string arguments.
+	// against segments of the log message words and argument identifiers.
 	// Example YAML:
 	//   sensitive_keywords:
 	//     - secret
 	//     - private_key
+	//   sensitive_keywords_mode: replace   # or "extend" (default)
+	//   sensitive_keywords_exclude:
+	//     - session
 	SensitiveKeywords []string `yaml:"sensitive_keywords"`
 
+	// SensitiveKeywordMatch overrides how individual keywords are matched
+	// against the camelCase/snake_case segments of message words and
+	// identifiers: "segment" (default) requires whole segments, "prefix"
+	// lets the keyword end inside a segment, "substring" matches anywhere.
+	// Example YAML:
+	//   sensitive_keyword_match:
+	//     auth: prefix
+	//     ssn: substring
+	SensitiveKeywordMatch map[string]string `yaml:"sensitive_keyword_match"`
+
+	// SafeNextWords lists words that suppress a sensitive keyword when they
+	// directly follow it in the message text ("token expired") or as the
+	// next identifier segment (sessionCount).
+	// Example YAML:
+	//   safe_next_words:
+	//     - expired
+	//     - rotated
+	SafeNextWords []string `yaml:"safe_next_words"`
+
+	// PIIDetectors selects the detectors run by the pii rule over message
+	// literals and constant arguments. All detectors are enabled by default.
+	// Example YAML:
+	//   pii_detectors: [email, phone, iban, card]
+	PIIDetectors []string `yaml:"pii_detectors"`
+
+	// Secrets configures the secrets rule, which looks for hard-coded
+	// credentials in message literals and constant arguments.
+	// Example YAML:
+	//   secrets:
+	//     entropy_threshold: 4.5
+	//     min_length: 24
+	//     detectors:
+	//       - name: internal_token
+	//         pattern: 'itk_[a-z0-9]{32}'
+	//     disable: [jwt]
+	Secrets SecretsConfig `yaml:"secrets"`
+
+	// Format toggles the sub-checks of the format rule. All are enabled by
+	// default.
+	// Example YAML:
+	//   format:
+	//     trailing_period: true
+	//     whitespace: true
+	//     control_chars: true
+	//     double_spaces: false
+	Format rules.FormatOptions `yaml:"format"`
+
+	// Length sets the bounds of the length rule, in runes of the constant
+	// portion of the message. Zero disables a bound.
+	// Example YAML:
+	//   length:
+	//     min_length: 3
+	//     max_length: 100
+	Length LengthConfig `yaml:"length"`
+
+	// Duplicate configures the duplicate rule, which reports constant
+	// messages used at more than MaxCallSites call sites of a package and
+	// the packages of the same module it imports.
+	// Example YAML:
+	//   duplicate:
+	//     max_call_sites: 2
+	//     allowed_messages: ["request failed"]
+	Duplicate DuplicateConfig `yaml:"duplicate"`
+
+	// EventID configures the eventid rule: slog and zap.Logger calls of the
+	// listed levels must pass the Key attribute with a constant value that
+	// matches Pattern and is unique across the package and the packages of
+	// the same module it imports.
+	// Example YAML:
+	//   eventid:
+	//     key: event_id
+	//     levels: [warn, error, fatal]
+	//     pattern: '^[a-z]+\.[a-z_]+$'
+	EventID EventIDConfig `yaml:"eventid"`
+
+	// Transliteration configures the fix of the english rule, which spells
+	// non-Latin letters of message literals with Latin ones. Tables selects
+	// the built-in tables (cyrillic, greek, latin; all by default) and
+	// Custom adds or overrides single characters.
+	// Example YAML:
+	//   transliteration:
+	//     tables: [cyrillic]
+	//     custom:
+	//       щ: sch
+	Transliteration TransliterationConfig `yaml:"transliteration"`
+
+	// Redaction configures the fix of the sensitive rule for sensitive
+	// attributes and message operands. With a helper, named by the import
+	// path of its package and the function, the value is wrapped in a call
+	// to it and the import is added; without one, the value is dropped.
+	// Example YAML:
+	//   redaction:
+	//     package: github.com/acme/app/internal/redact
+	//     function: String
+	Redaction RedactionConfig `yaml:"redaction"`
+
+	// SpellingWords extends the dictionary of the spelling rule with the
+	// project's own words, such as product names and domain terms. Words
+	// are case-insensitive, and lists from several config files add up.
+	// Example YAML:
+	//   spelling_words:
+	//     - acmepay
+	//     - reindexer
+	SpellingWords []string `yaml:"spelling_words"`
+
+	// FatalAllowedPackages lists import paths, besides package main, where
+	// the fatal rule allows Fatal- and Panic-level log calls. A trailing
+	// "/..." matches the package and everything below it.
+	// Example YAML:
+	//   fatal_allowed_packages:
+	//     - github.com/acme/app/internal/bootstrap/...
+	FatalAllowedPackages []string `yaml:"fatal_allowed_packages"`
+
+	// LogReturnAllowedPackages lists import paths of logging boundaries,
+	// such as HTTP handlers, where the logreturn rule allows logging an
+	// error and returning it. A trailing "/..." matches nested packages.
+	// Example YAML:
+	//   logreturn_allowed_packages:
+	//     - github.com/acme/app/internal/handlers/...
+	LogReturnAllowedPackages []string `yaml:"logreturn_allowed_packages"`
+
+	// AllowedScripts lists the Unicode scripts, by their names in the Go
+	// unicode package ("Latin", "Cyrillic", "Han", ...), whose characters
+	// the english rule accepts besides ASCII. The default is Latin, which
+	// covers accented names such as "Zürich"; an empty list allows ASCII
+	// only.
+	// Example YAML:
+	//   allowed_scripts: [Latin, Greek]
+	AllowedScripts []string `yaml:"allowed_scripts"`
+
+	// AllowedRunes lists single characters the english rule accepts on top
+	// of the allowed scripts, such as currency signs. Dashes, curly quotes
+	// and the ellipsis are always accepted.
+	// Example YAML:
+	//   allowed_runes: ["€", "°"]
+	AllowedRunes []string `yaml:"allowed_runes"`
+
 	// AllowedSpecialChars lists characters that should NOT be flagged by the
 	// special-characters rule. Useful for allowing punctuation like colons.
 	// Example YAML:
@@ -42,74 +256,410 @@ type Config struct {
 	AllowedSpecialChars string `yaml:"allowed_special_chars"`
 }
 
-// DefaultConfig returns a configuration with all rules enabled and a
+// SecretsConfig holds the resolved settings of the secrets rule.
+type SecretsConfig struct {
+	// Detectors are the credential formats to look for: the built-in ones
+	// plus any defined in config files, minus disabled ones.
+	Detectors []rules.SecretDetector
+	// EntropyThreshold is the minimum Shannon entropy (bits per character)
+	// of a token reported as a likely secret; zero disables the check.
+	EntropyThreshold float64
+	// MinLength is the minimum length of a token considered by the entropy
+	// check.
+	MinLength int
+}
+
+// LengthConfig holds the bounds of the length rule.
+type LengthConfig struct {
+	MinLength int `yaml:"min_length"`
+	MaxLength int `yaml:"max_length"`
+}
+
+// DuplicateConfig holds the settings of the duplicate rule.
+type DuplicateConfig struct {
+	// MaxCallSites is the number of call sites a message may have before it
+	// is reported.
+	MaxCallSites int `yaml:"max_call_sites"`
+	// AllowedMessages are intentionally shared messages, never reported.
+	AllowedMessages []string `yaml:"allowed_messages"`
+}
+
+// EventIDConfig holds the settings of the eventid rule.
+type EventIDConfig struct {
+	// Key is the attribute key that holds the event ID.
+	Key string `yaml:"key"`
+	// Levels are the level names that require an event ID: debug, info,
+	// warn, error, panic or fatal.
+	Levels []string `yaml:"levels"`
+	// Pattern is a regular expression event IDs must match; empty accepts
+	// any ID.
+	Pattern string `yaml:"pattern"`
+}
+
+// TransliterationConfig holds the settings of the english rule's fix.
+type TransliterationConfig struct {
+	// Tables are the names of the built-in tables: cyrillic, greek or
+	// latin (letters with diacritics).
+	Tables []string `yaml:"tables"`
+	// Custom maps single characters to their Latin spelling and takes
+	// precedence over the tables.
+	Custom map[string]string `yaml:"custom"`
+}
+
+// RedactionConfig names the helper the sensitive rule's fix wraps values
+// in, such as redact.String; both fields are empty when values are dropped.
+type RedactionConfig struct {
+	// Package is the import path of the package declaring the helper.
+	Package string `yaml:"package"`
+	// Function is the name of the helper, which takes the sensitive value.
+	Function string `yaml:"function"`
+}
+
+// DefaultConfig returns a configuration with the default rules enabled and a
 // sensible set of sensitive keywords.
 func DefaultConfig() *Config {
+	enabled := make(map[string]bool, len(defaultRules))
+	for name, on := range defaultRules {
+		enabled[name] = on
+	}
 	return &Config{
-		Rules: map[string]bool{
-			RuleLowercase: true,
-			RuleEnglish:   true,
-			RuleSpecial:   true,
-			RuleSensitive: true,
-		},
+		Rules:             enabled,
 		SensitiveKeywords: defaultSensitiveKeywords(),
+		SafeNextWords:     rules.DefaultSafeNextWords(),
+		PIIDetectors:      rules.PIIDetectorNames(),
+		Format:            rules.AllFormatChecks,
+		Length: LengthConfig{
+			MinLength: rules.DefaultMinMessageLength,
+			MaxLength: rules.DefaultMaxMessageLength,
+		},
+		Duplicate: DuplicateConfig{
+			MaxCallSites: rules.DefaultMaxCallSites,
+		},
+		EventID: EventIDConfig{
+			Key:     rules.DefaultEventIDKey,
+			Levels:  rules.DefaultEventIDLevels(),
+			Pattern: rules.DefaultEventIDPattern,
+		},
+		AllowedScripts: rules.DefaultAllowedScripts(),
+		Transliteration: TransliterationConfig{
+			Tables: rules.TransliterationTableNames(),
+		},
+		Secrets: SecretsConfig{
+			Detectors:        rules.BuiltinSecretDetectors(),
+			EntropyThreshold: rules.DefaultEntropyThreshold,
+			MinLength:        rules.DefaultEntropyMinLength,
+		},
 	}
 }
 
 // IsRuleEnabled reports whether the named rule should run.
-// Unknown rule names default to enabled so that new rules are active by
-// default even if the user has not updated their config file.
+// Rules missing from c.Rules fall back to their built-in default; unknown
+// rule names are never enabled.
 func (c *Config) IsRuleEnabled(name string) bool {
-	if c.Rules == nil {
-		return true
-	}
-	enabled, ok := c.Rules[name]
-	if !ok {
-		return true
+	if enabled, ok := c.Rules[name]; ok {
+		return enabled
 	}
-	return enabled
+	return defaultRules[name]
+}
+
+// fileConfig mirrors the YAML layout of a config file. We unmarshal into it
+// rather than into Config so we can selectively merge only the fields that
+// were actually present in the file.
+type fileConfig struct {
+	Rules                    map[string]bool   `yaml:"rules"`
+	SensitiveKeywords        []string          `yaml:"sensitive_keywords"`
+	SensitiveKeywordsMode    string            `yaml:"sensitive_keywords_mode"`
+	SensitiveKeywordsExclude []string          `yaml:"sensitive_keywords_exclude"`
+	SensitiveKeywordMatch    map[string]string `yaml:"sensitive_keyword_match"`
+	SafeNextWords            []string          `yaml:"safe_next_words"`
+	PIIDetectors             []string          `yaml:"pii_detectors"`
+	Secrets                  fileSecrets       `yaml:"secrets"`
+	Format                   fileFormat        `yaml:"format"`
+	Length                   fileLength        `yaml:"length"`
+	Duplicate                fileDuplicate     `yaml:"duplicate"`
+	EventID                  fileEventID       `yaml:"eventid"`
+	Transliteration          fileTranslit      `yaml:"transliteration"`
+	Redaction                fileRedaction     `yaml:"redaction"`
+	SpellingWords            []string          `yaml:"spelling_words"`
+	FatalAllowedPackages     []string          `yaml:"fatal_allowed_packages"`
+	LogReturnAllowedPackages []string          `yaml:"logreturn_allowed_packages"`
+	AllowedScripts           []string          `yaml:"allowed_scripts"`
+	AllowedRunes             []string          `yaml:"allowed_runes"`
+	AllowedSpecialChars      *string           `yaml:"allowed_special_chars"`
+}
+
+// fileSecrets mirrors the secrets section of a config file. Pointer fields
+// distinguish "not set" from zero values.
+type fileSecrets struct {
+	EntropyThreshold *float64             `yaml:"entropy_threshold"`
+	MinLength        *int                 `yaml:"min_length"`
+	Detectors        []fileSecretDetector `yaml:"detectors"`
+	Disable          []string             `yaml:"disable"`
+}
+
+// fileFormat mirrors the format section of a config file.
+type fileFormat struct {
+	TrailingPeriod *bool `yaml:"trailing_period"`
+	Whitespace     *bool `yaml:"whitespace"`
+	ControlChars   *bool `yaml:"control_chars"`
+	DoubleSpaces   *bool `yaml:"double_spaces"`
+}
+
+// fileLength mirrors the length section of a config file.
+type fileLength struct {
+	MinLength *int `yaml:"min_length"`
+	MaxLength *int `yaml:"max_length"`
 }
 
+// fileDuplicate mirrors the duplicate section of a config file.
+type fileDuplicate struct {
+	MaxCallSites    *int     `yaml:"max_call_sites"`
+	AllowedMessages []string `yaml:"allowed_messages"`
+}
+
+// fileEventID mirrors the eventid section of a config file.
+type fileEventID struct {
+	Key     *string  `yaml:"key"`
+	Levels  []string `yaml:"levels"`
+	Pattern *string  `yaml:"pattern"`
+}
+
+// fileTranslit mirrors the transliteration section of a config file.
+type fileTranslit struct {
+	Tables []string          `yaml:"tables"`
+	Custom map[string]string `yaml:"custom"`
+}
+
+// fileRedaction mirrors the redaction section of a config file.
+type fileRedaction struct {
+	Package  *string `yaml:"package"`
+	Function *string `yaml:"function"`
+}
+
+type fileSecretDetector struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+// Values accepted by the sensitive_keywords_mode key.
+const (
+	KeywordsExtend  = "extend"
+	KeywordsReplace = "replace"
+)
+
 // Load reads a YAML config file from path and merges it on top of the
 // default configuration. Missing fields keep their default values.
+//
+// Decoding is strict: unknown keys, unknown rule names and invalid values are
+// reported as an *Error carrying the file position of the offending node.
 func Load(path string) (*Config, error) {
+	return LoadFiles(path)
+}
+
+// LoadFiles merges the given config files on top of the default
+// configuration in order, so that later files override earlier ones. Files
+// that do not exist are skipped.
+func LoadFiles(paths ...string) (*Config, error) {
 	cfg := DefaultConfig()
+	for _, path := range paths {
+		if err := cfg.mergeFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
 
+// mergeFile reads the config file at path and applies the fields it sets.
+func (c *Config) mergeFile(path string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			// No config file is perfectly fine – use defaults.
-			return cfg, nil
+			// No config file is perfectly fine – keep what we have.
+			return nil
 		}
-		return nil, fmt.Errorf("loglinter: reading config %q: %w", path, err)
+		return fmt.Errorf("loglinter: reading config %q: %w", path, err)
+	}
+
+	file, err := parse(path, data)
+	if err != nil {
+		return err
 	}
 
-	// We unmarshal into a temporary struct so we can selectively merge only
-	// the fields that were actually present in the file.
-	var file struct {
-		Rules               map[string]bool `yaml:"rules"`
-		SensitiveKeywords   []string        `yaml:"sensitive_keywords"`
-		AllowedSpecialChars string          `yaml:"allowed_special_chars"`
+	for k, v := range file.Rules {
+		c.Rules[k] = v
+	}
+	switch {
+	case file.SensitiveKeywordsMode == KeywordsReplace:
+		// Drop the inherited list entirely, even if no keywords are given.
+		c.SensitiveKeywords = file.SensitiveKeywords
+	case len(file.SensitiveKeywords) > 0:
+		// Extend inherited keywords with user-supplied ones.
+		c.SensitiveKeywords = slices.Concat(c.SensitiveKeywords, file.SensitiveKeywords)
+	}
+	if len(file.SensitiveKeywordsExclude) > 0 {
+		c.SensitiveKeywords = excludeWords(c.SensitiveKeywords, file.SensitiveKeywordsExclude)
+	}
+	if len(file.SensitiveKeywordMatch) > 0 {
+		modes := make(map[string]string, len(c.SensitiveKeywordMatch)+len(file.SensitiveKeywordMatch))
+		for kw, mode := range c.SensitiveKeywordMatch {
+			modes[kw] = mode
+		}
+		for kw, mode := range file.SensitiveKeywordMatch {
+			modes[kw] = mode
+		}
+		c.SensitiveKeywordMatch = modes
+	}
+	if file.SafeNextWords != nil {
+		// A user-supplied list replaces the inherited one.
+		c.SafeNextWords = file.SafeNextWords
+	}
+	if file.PIIDetectors != nil {
+		c.PIIDetectors = file.PIIDetectors
+	}
+	c.Secrets.merge(&file.Secrets)
+	if file.Length.MinLength != nil {
+		c.Length.MinLength = *file.Length.MinLength
+	}
+	if file.Length.MaxLength != nil {
+		c.Length.MaxLength = *file.Length.MaxLength
+	}
+	if file.Duplicate.MaxCallSites != nil {
+		c.Duplicate.MaxCallSites = *file.Duplicate.MaxCallSites
 	}
+	if file.Duplicate.AllowedMessages != nil {
+		c.Duplicate.AllowedMessages = file.Duplicate.AllowedMessages
+	}
+	if file.EventID.Key != nil {
+		c.EventID.Key = *file.EventID.Key
+	}
+	if file.EventID.Levels != nil {
+		c.EventID.Levels = file.EventID.Levels
+	}
+	if file.EventID.Pattern != nil {
+		c.EventID.Pattern = *file.EventID.Pattern
+	}
+	if file.Transliteration.Tables != nil {
+		c.Transliteration.Tables = file.Transliteration.Tables
+	}
+	if len(file.Transliteration.Custom) > 0 {
+		custom := make(map[string]string, len(c.Transliteration.Custom)+len(file.Transliteration.Custom))
+		for from, to := range c.Transliteration.Custom {
+			custom[from] = to
+		}
+		for from, to := range file.Transliteration.Custom {
+			custom[from] = to
+		}
+		c.Transliteration.Custom = custom
+	}
+	if file.Redaction.Package != nil {
+		c.Redaction.Package = *file.Redaction.Package
+	}
+	if file.Redaction.Function != nil {
+		c.Redaction.Function = *file.Redaction.Function
+	}
+	if len(file.SpellingWords) > 0 {
+		c.SpellingWords = slices.Concat(c.SpellingWords, file.SpellingWords)
+	}
+	for _, f := range []struct {
+		dst *bool
+		src *bool
+	}{
+		{&c.Format.TrailingPeriod, file.Format.TrailingPeriod},
+		{&c.Format.Whitespace, file.Format.Whitespace},
+		{&c.Format.ControlChars, file.Format.ControlChars},
+		{&c.Format.DoubleSpaces, file.Format.DoubleSpaces},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	if file.FatalAllowedPackages != nil {
+		c.FatalAllowedPackages = file.FatalAllowedPackages
+	}
+	if file.LogReturnAllowedPackages != nil {
+		c.LogReturnAllowedPackages = file.LogReturnAllowedPackages
+	}
+	if file.AllowedScripts != nil {
+		c.AllowedScripts = file.AllowedScripts
+	}
+	if file.AllowedRunes != nil {
+		c.AllowedRunes = file.AllowedRunes
+	}
+	if file.AllowedSpecialChars != nil {
+		c.AllowedSpecialChars = *file.AllowedSpecialChars
+	}
+	return nil
+}
 
-	if err := yaml.Unmarshal(data, &file); err != nil {
-		return nil, fmt.Errorf("loglinter: parsing config %q: %w", path, err)
+// merge applies the secrets section of a config file. A detector with the
+// name of an inherited one replaces it; disabled detectors are removed.
+func (s *SecretsConfig) merge(file *fileSecrets) {
+	if file.EntropyThreshold != nil {
+		s.EntropyThreshold = *file.EntropyThreshold
+	}
+	if file.MinLength != nil {
+		s.MinLength = *file.MinLength
+	}
+	if len(file.Detectors) == 0 && len(file.Disable) == 0 {
+		return
 	}
 
-	if file.Rules != nil {
-		for k, v := range file.Rules {
-			cfg.Rules[k] = v
+	detectors := slices.Clone(s.Detectors)
+	for _, fd := range file.Detectors {
+		d := rules.SecretDetector{Name: fd.Name, Pattern: fd.Pattern}
+		if i := slices.IndexFunc(detectors, func(x rules.SecretDetector) bool { return x.Name == d.Name }); i >= 0 {
+			detectors[i] = d
+		} else {
+			detectors = append(detectors, d)
 		}
 	}
-	if len(file.SensitiveKeywords) > 0 {
-		// Extend defaults with user-supplied keywords.
-		cfg.SensitiveKeywords = append(cfg.SensitiveKeywords, file.SensitiveKeywords...)
+	s.Detectors = slices.DeleteFunc(detectors, func(d rules.SecretDetector) bool {
+		return slices.Contains(file.Disable, d.Name)
+	})
+}
+
+// parse validates and strictly decodes the contents of a config file.
+func parse(path string, data []byte) (*fileConfig, error) {
+	var file fileConfig
+
+	// First pass: build the node tree so that semantic errors can be reported
+	// with the position of the offending key or value.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, yamlError(path, err)
+	}
+	if len(doc.Content) == 0 {
+		// Empty file (or only comments).
+		return &file, nil
 	}
-	if file.AllowedSpecialChars != "" {
-		cfg.AllowedSpecialChars = file.AllowedSpecialChars
+	if err := validate(path, doc.Content[0]); err != nil {
+		return nil, err
 	}
 
-	return cfg, nil
+	// Second pass: strict decoding catches anything validate does not know
+	// about, such as type mismatches.
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, yamlError(path, err)
+	}
+	return &file, nil
+}
+
+// excludeWords returns words without the entries of exclude, compared
+// case-insensitively.
+func excludeWords(words, exclude []string) []string {
+	drop := make(map[string]struct{}, len(exclude))
+	for _, w := range exclude {
+		drop[strings.ToLower(w)] = struct{}{}
+	}
+	kept := make([]string, 0, len(words))
+	for _, w := range words {
+		if _, ok := drop[strings.ToLower(w)]; !ok {
+			kept = append(kept, w)
+		}
+	}
+	return kept
 }
 
 // defaultSensitiveKeywords returns the built-in list of keywords that
@@ -1,11 +1,15 @@
 package config_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/Wladim1r/loglinter/internal/config"
+	"github.com/Wladim1r/loglinter/internal/rules"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -17,6 +21,7 @@ func TestDefaultConfig(t *testing.T) {
 		config.RuleEnglish,
 		config.RuleSpecial,
 		config.RuleSensitive,
+		config.RulePII,
 	} {
 		if !cfg.IsRuleEnabled(rule) {
 			t.Errorf("expected rule %q to be enabled by default", rule)
@@ -31,9 +36,9 @@ func TestDefaultConfig(t *testing.T) {
 func TestIsRuleEnabled_UnknownRule(t *testing.T) {
 	t.Parallel()
 	cfg := config.DefaultConfig()
-	// Unknown rule names should default to enabled.
-	if !cfg.IsRuleEnabled("nonexistent_rule") {
-		t.Error("unknown rule should default to enabled")
+	// Unknown rule names are never enabled.
+	if cfg.IsRuleEnabled("nonexistent_rule") {
+		t.Error("unknown rule should not be enabled")
 	}
 }
 
@@ -90,6 +95,280 @@ allowed_special_chars: "!"
 	}
 }
 
+func TestLoad_SensitiveKeywordsMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		want    []string
+		notWant []string
+	}{
+		{
+			"extend by default",
+			"sensitive_keywords: [my_secret]\n",
+			[]string{"password", "my_secret"},
+			nil,
+		},
+		{
+			"explicit extend",
+			"sensitive_keywords_mode: extend\nsensitive_keywords: [my_secret]\n",
+			[]string{"password", "my_secret"},
+			nil,
+		},
+		{
+			"replace",
+			"sensitive_keywords_mode: replace\nsensitive_keywords: [my_secret]\n",
+			[]string{"my_secret"},
+			[]string{"password", "session"},
+		},
+		{
+			"exclude defaults",
+			"sensitive_keywords_exclude: [Session, auth]\n",
+			[]string{"password", "token"},
+			[]string{"session", "auth"},
+		},
+		{
+			"exclude after extend",
+			"sensitive_keywords: [my_secret]\nsensitive_keywords_exclude: [my_secret]\n",
+			[]string{"password"},
+			[]string{"my_secret"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := config.Load(writeTempFile(t, tc.yaml))
+			if err != nil {
+				t.Fatalf("Load returned error: %v", err)
+			}
+			have := make(map[string]bool, len(cfg.SensitiveKeywords))
+			for _, kw := range cfg.SensitiveKeywords {
+				have[kw] = true
+			}
+			for _, kw := range tc.want {
+				if !have[kw] {
+					t.Errorf("expected keyword %q in %v", kw, cfg.SensitiveKeywords)
+				}
+			}
+			for _, kw := range tc.notWant {
+				if have[kw] {
+					t.Errorf("did not expect keyword %q in %v", kw, cfg.SensitiveKeywords)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_SafeNextWords(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.DefaultConfig()
+	if len(cfg.SafeNextWords) == 0 {
+		t.Fatal("expected non-empty default safe next words")
+	}
+
+	cfg, err := config.Load(writeTempFile(t, "safe_next_words: [manager, store]\n"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(cfg.SafeNextWords) != 2 || cfg.SafeNextWords[0] != "manager" {
+		t.Errorf("expected safe_next_words to replace defaults, got %v", cfg.SafeNextWords)
+	}
+}
+
+func TestLoad_SensitiveKeywordMatch(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.Load(writeTempFile(t, "sensitive_keyword_match:\n  auth: prefix\n  ssn: substring\n"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.SensitiveKeywordMatch["auth"] != "prefix" || cfg.SensitiveKeywordMatch["ssn"] != "substring" {
+		t.Errorf("unexpected SensitiveKeywordMatch %v", cfg.SensitiveKeywordMatch)
+	}
+}
+
+func TestLoad_Secrets(t *testing.T) {
+	t.Parallel()
+	f := writeTempFile(t, `
+secrets:
+  entropy_threshold: 4.5
+  detectors:
+    - name: internal_token
+      pattern: 'itk_[a-z0-9]{32}'
+    - name: jwt
+      pattern: 'jwt:[A-Za-z0-9.]+'
+  disable: [slack_token]
+`)
+	cfg, err := config.Load(f)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Secrets.EntropyThreshold != 4.5 {
+		t.Errorf("EntropyThreshold = %v, want 4.5", cfg.Secrets.EntropyThreshold)
+	}
+	if cfg.Secrets.MinLength != rules.DefaultEntropyMinLength {
+		t.Errorf("MinLength = %d, want the default", cfg.Secrets.MinLength)
+	}
+	patterns := make(map[string]string)
+	for _, d := range cfg.Secrets.Detectors {
+		patterns[d.Name] = d.Pattern
+	}
+	if _, ok := patterns["slack_token"]; ok {
+		t.Error("expected slack_token to be disabled")
+	}
+	if patterns["internal_token"] == "" || patterns["aws_access_key"] == "" {
+		t.Errorf("expected built-in and custom detectors, got %v", patterns)
+	}
+	if patterns["jwt"] != "jwt:[A-Za-z0-9.]+" {
+		t.Errorf("expected jwt detector to be redefined, got %q", patterns["jwt"])
+	}
+}
+
+func TestLoad_Format(t *testing.T) {
+	t.Parallel()
+	f := writeTempFile(t, "rules:\n  format: true\nformat:\n  double_spaces: false\n")
+	cfg, err := config.Load(f)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.IsRuleEnabled(config.RuleFormat) {
+		t.Error("expected format rule to be enabled")
+	}
+	want := rules.AllFormatChecks
+	want.DoubleSpaces = false
+	if cfg.Format != want {
+		t.Errorf("Format = %+v, want %+v", cfg.Format, want)
+	}
+}
+
+func TestLoad_Length(t *testing.T) {
+	t.Parallel()
+	f := writeTempFile(t, "length:\n  max_length: 80\n")
+	cfg, err := config.Load(f)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	want := config.LengthConfig{MinLength: rules.DefaultMinMessageLength, MaxLength: 80}
+	if cfg.Length != want {
+		t.Errorf("Length = %+v, want %+v", cfg.Length, want)
+	}
+}
+
+func TestLoad_Duplicate(t *testing.T) {
+	t.Parallel()
+	f := writeTempFile(t, "duplicate:\n  allowed_messages: [request failed]\n")
+	cfg, err := config.Load(f)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	want := config.DuplicateConfig{
+		MaxCallSites:    rules.DefaultMaxCallSites,
+		AllowedMessages: []string{"request failed"},
+	}
+	if !reflect.DeepEqual(cfg.Duplicate, want) {
+		t.Errorf("Duplicate = %+v, want %+v", cfg.Duplicate, want)
+	}
+}
+
+func TestLoadFiles_SpellingWords(t *testing.T) {
+	t.Parallel()
+	base := writeTempFile(t, "spelling_words: [acmepay]\n")
+	local := writeTempFile(t, "spelling_words: [reindexer]\n")
+	cfg, err := config.LoadFiles(base, local)
+	if err != nil {
+		t.Fatalf("LoadFiles returned error: %v", err)
+	}
+	if want := []string{"acmepay", "reindexer"}; !reflect.DeepEqual(cfg.SpellingWords, want) {
+		t.Errorf("SpellingWords = %q, want %q", cfg.SpellingWords, want)
+	}
+}
+
+func TestLoadFiles_Transliteration(t *testing.T) {
+	t.Parallel()
+	base := writeTempFile(t, "transliteration:\n  tables: [cyrillic]\n  custom:\n    щ: sch\n")
+	local := writeTempFile(t, "transliteration:\n  custom:\n    х: h\n")
+	cfg, err := config.LoadFiles(base, local)
+	if err != nil {
+		t.Fatalf("LoadFiles returned error: %v", err)
+	}
+	want := config.TransliterationConfig{
+		Tables: []string{rules.TableCyrillic},
+		Custom: map[string]string{"щ": "sch", "х": "h"},
+	}
+	if !reflect.DeepEqual(cfg.Transliteration, want) {
+		t.Errorf("Transliteration = %+v, want %+v", cfg.Transliteration, want)
+	}
+}
+
+func TestLoadFiles_Redaction(t *testing.T) {
+	t.Parallel()
+	base := writeTempFile(t, "redaction:\n  package: example.com/redact\n  function: String\n")
+	local := writeTempFile(t, "redaction:\n  package: example.com/internal/mask\n  function: Secret\n")
+	cfg, err := config.LoadFiles(base, local)
+	if err != nil {
+		t.Fatalf("LoadFiles returned error: %v", err)
+	}
+	want := config.RedactionConfig{Package: "example.com/internal/mask", Function: "Secret"}
+	if cfg.Redaction != want {
+		t.Errorf("Redaction = %+v, want %+v", cfg.Redaction, want)
+	}
+
+	cfg, err = config.Load(base)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if want := (config.RedactionConfig{Package: "example.com/redact", Function: "String"}); cfg.Redaction != want {
+		t.Errorf("Redaction = %+v, want %+v", cfg.Redaction, want)
+	}
+}
+
+func TestLoadFiles_AllowedSpecialChars(t *testing.T) {
+	t.Parallel()
+	base := writeTempFile(t, "allowed_special_chars: \"!\"\n")
+	for _, tc := range []struct {
+		local string
+		want  string
+	}{
+		{"rules:\n  special: true\n", "!"},
+		{"allowed_special_chars: \"-\"\n", "-"},
+		{"allowed_special_chars: \"\"\n", ""},
+	} {
+		cfg, err := config.LoadFiles(base, writeTempFile(t, tc.local))
+		if err != nil {
+			t.Fatalf("LoadFiles returned error: %v", err)
+		}
+		if cfg.AllowedSpecialChars != tc.want {
+			t.Errorf("%q: AllowedSpecialChars = %q, want %q", tc.local, cfg.AllowedSpecialChars, tc.want)
+		}
+	}
+}
+
+func TestLoad_AllowedScripts(t *testing.T) {
+	t.Parallel()
+	cfg, err := config.Load(writeTempFile(t, "allowed_runes: [\"€\"]\n"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if want := rules.DefaultAllowedScripts(); !reflect.DeepEqual(cfg.AllowedScripts, want) {
+		t.Errorf("AllowedScripts = %q, want the default %q", cfg.AllowedScripts, want)
+	}
+	if want := []string{"€"}; !reflect.DeepEqual(cfg.AllowedRunes, want) {
+		t.Errorf("AllowedRunes = %q, want %q", cfg.AllowedRunes, want)
+	}
+
+	cfg, err = config.Load(writeTempFile(t, "allowed_scripts: []\n"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(cfg.AllowedScripts) != 0 {
+		t.Errorf("AllowedScripts = %q, want an empty list to allow ASCII only", cfg.AllowedScripts)
+	}
+}
+
 func TestLoad_InvalidYAML(t *testing.T) {
 	t.Parallel()
 	f := writeTempFile(t, "rules: [invalid yaml }{")
@@ -99,6 +378,205 @@ func TestLoad_InvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoad_StrictErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		yaml     string
+		wantLine int
+		wantMsg  string
+	}{
+		{
+			"unknown top-level key",
+			"rule:\n  sensitive: false\n",
+			1,
+			`unknown key "rule" (did you mean "rules"?)`,
+		},
+		{
+			"misspelled rule name",
+			"rules:\n  lowercase: true\n  sensitve: false\n",
+			3,
+			`unknown rule "sensitve" (did you mean "sensitive"?)`,
+		},
+		{
+			"letter in allowed_special_chars",
+			"allowed_special_chars: \"!a\"\n",
+			1,
+			`invalid character 'a' at index 1`,
+		},
+		{
+			"non-ascii in allowed_special_chars",
+			"\nallowed_special_chars: \"—\"\n",
+			2,
+			`invalid character '—'`,
+		},
+		{
+			"wrong value type",
+			"rules:\n  sensitive: maybe\n",
+			2,
+			"cannot unmarshal",
+		},
+		{
+			"invalid keywords mode",
+			"sensitive_keywords_mode: append\n",
+			1,
+			`sensitive_keywords_mode: invalid value "append"`,
+		},
+		{
+			"invalid keyword match mode",
+			"sensitive_keyword_match:\n  auth: fuzzy\n",
+			2,
+			`sensitive_keyword_match: invalid mode "fuzzy" for keyword "auth"`,
+		},
+		{
+			"unknown pii detector",
+			"pii_detectors:\n  - email\n  - ipv5\n",
+			3,
+			`pii_detectors: unknown detector "ipv5" (did you mean "ipv4"?)`,
+		},
+		{
+			"invalid secret pattern",
+			"secrets:\n  detectors:\n    - name: internal\n      pattern: 'itk_[a-z'\n",
+			4,
+			`secrets.detectors: invalid pattern for "internal"`,
+		},
+		{
+			"unknown disabled secret detector",
+			"secrets:\n  disable: [slack_tokn]\n",
+			2,
+			`secrets.disable: unknown detector "slack_tokn" (did you mean "slack_token"?)`,
+		},
+		{
+			"entropy threshold out of range",
+			"secrets:\n  entropy_threshold: 9\n",
+			2,
+			"secrets.entropy_threshold: 9 is out of range",
+		},
+		{
+			"negative length",
+			"length:\n  min_length: -1\n",
+			2,
+			"length.min_length: must not be negative",
+		},
+		{
+			"max length below min length",
+			"length:\n  min_length: 10\n  max_length: 5\n",
+			3,
+			"length.max_length: 5 is below min_length 10",
+		},
+		{
+			"zero duplicate call sites",
+			"duplicate:\n  max_call_sites: 0\n",
+			2,
+			"duplicate.max_call_sites: must be at least 1",
+		},
+		{
+			"unknown event ID level",
+			"eventid:\n  levels: [warn, eror]\n",
+			2,
+			`eventid.levels: unknown level "eror"`,
+		},
+		{
+			"invalid event ID pattern",
+			"eventid:\n  key: event_id\n  pattern: '[a-z'\n",
+			3,
+			"eventid.pattern:",
+		},
+		{
+			"unknown transliteration table",
+			"transliteration:\n  tables: [cyrilic]\n",
+			2,
+			`transliteration.tables: unknown table "cyrilic" (did you mean "cyrillic"?)`,
+		},
+		{
+			"multi-character transliteration key",
+			"transliteration:\n  custom:\n    щи: shchi\n",
+			3,
+			`transliteration.custom: key "щи" must be a single character`,
+		},
+		{
+			"non-ASCII transliteration",
+			"transliteration:\n  custom:\n    щ: ŝ\n",
+			3,
+			`transliteration.custom: spelling "ŝ" of "щ" must be ASCII`,
+		},
+		{
+			"unknown script",
+			"allowed_scripts: [Latin, cyrillic]\n",
+			1,
+			`allowed_scripts: unknown script "cyrillic" (did you mean "Cyrillic"?)`,
+		},
+		{
+			"multi-character allowed rune",
+			"allowed_runes:\n  - \"€\"\n  - \"°C\"\n",
+			3,
+			`allowed_runes: "°C" must be a single character`,
+		},
+		{
+			"redaction package not an import path",
+			"redaction:\n  package: \"example.com/redact/\"\n  function: String\n",
+			2,
+			`redaction.package: "example.com/redact/" is not an import path`,
+		},
+		{
+			"unexported redaction function",
+			"redaction:\n  package: example.com/redact\n  function: mask\n",
+			3,
+			`redaction.function: "mask" is not an exported function name`,
+		},
+		{
+			"redaction package without function",
+			"redaction:\n  package: example.com/redact\n",
+			2,
+			"redaction: package and function must be set together",
+		},
+		{
+			"top-level sequence",
+			"- rules\n",
+			1,
+			"top-level value must be a mapping",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			f := writeTempFile(t, tc.yaml)
+			_, err := config.Load(f)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			var cfgErr *config.Error
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("expected *config.Error, got %T: %v", err, err)
+			}
+			if cfgErr.Line != tc.wantLine {
+				t.Errorf("Line = %d, want %d (%v)", cfgErr.Line, tc.wantLine, err)
+			}
+			if !strings.Contains(cfgErr.Msg, tc.wantMsg) {
+				t.Errorf("Msg = %q, want it to contain %q", cfgErr.Msg, tc.wantMsg)
+			}
+			if !strings.Contains(err.Error(), f) {
+				t.Errorf("error %q does not mention the file path", err)
+			}
+		})
+	}
+}
+
+func TestLoad_EmptyFile(t *testing.T) {
+	t.Parallel()
+	f := writeTempFile(t, "# only a comment\n")
+	cfg, err := config.Load(f)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.IsRuleEnabled(config.RuleLowercase) {
+		t.Error("expected defaults for an empty file")
+	}
+}
+
 func writeTempFile(t *testing.T, content string) string {
 	t.Helper()
 	dir := t.TempDir()
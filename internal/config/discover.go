@@ -0,0 +1,98 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the name of the configuration file looked up by Discover.
+const FileName = ".loglinter.yaml"
+
+// Discover returns the paths of the config files that apply to a package in
+// dir, ordered from the outermost to the innermost directory.
+//
+// The search walks up from dir to the project root: the directory holding the
+// nearest go.work file or, when there is none, the nearest go.mod. Outside of
+// any module only dir itself is considered.
+func Discover(dir string) []string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	root := projectRoot(dir)
+	if root == "" {
+		root = dir
+	}
+
+	var paths []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if p := filepath.Join(d, FileName); isFile(p) {
+			paths = append(paths, p)
+		}
+		if d == root || d == filepath.Dir(d) {
+			break
+		}
+	}
+
+	// Outermost first so that nested files override their parents.
+	for i, j := 0, len(paths)-1; i < j; i, j = i+1, j-1 {
+		paths[i], paths[j] = paths[j], paths[i]
+	}
+	return paths
+}
+
+// Resolve loads the configuration for a package in dir by merging every file
+// returned by Discover on top of the defaults.
+func Resolve(dir string) (*Config, error) {
+	return LoadFiles(Discover(dir)...)
+}
+
+// DiscoverNested returns the paths of the config files in the
+// subdirectories of dir. Like the go tool, it skips vendor and testdata
+// directories and those whose names start with "." or "_".
+func DiscoverNested(dir string) []string {
+	var paths []string
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if d.Name() == FileName && filepath.Dir(path) != filepath.Clean(dir) {
+				paths = append(paths, path)
+			}
+			return nil
+		}
+		name := d.Name()
+		if path != dir && (name == "vendor" || name == "testdata" ||
+			strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return paths
+}
+
+// projectRoot returns the go.work root enclosing dir, the module root when
+// there is no workspace, or "" if dir is not inside a module.
+func projectRoot(dir string) string {
+	moduleRoot := ""
+	for d := dir; ; d = filepath.Dir(d) {
+		if isFile(filepath.Join(d, "go.work")) {
+			return d
+		}
+		if moduleRoot == "" && isFile(filepath.Join(d, "go.mod")) {
+			moduleRoot = d
+		}
+		if d == filepath.Dir(d) {
+			return moduleRoot
+		}
+	}
+}
+
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
@@ -0,0 +1,144 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Wladim1r/loglinter/internal/config"
+)
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	// workspace/
+	//   go.work
+	//   .loglinter.yaml
+	//   svc/
+	//     go.mod
+	//     .loglinter.yaml
+	//     internal/db/          <- package directory
+	//   lib/
+	//     go.mod
+	//     pkg/                  <- package directory without own config
+	// standalone/
+	//   go.mod
+	//   cmd/                    <- module without workspace
+	base := t.TempDir()
+	writeFile(t, base, "workspace/go.work", "go 1.25\n")
+	writeFile(t, base, "workspace/.loglinter.yaml", "rules:\n  special: false\n")
+	writeFile(t, base, "workspace/svc/go.mod", "module svc\n")
+	writeFile(t, base, "workspace/svc/.loglinter.yaml", "rules:\n  lowercase: false\n")
+	writeFile(t, base, "workspace/svc/internal/db/db.go", "package db\n")
+	writeFile(t, base, "workspace/lib/go.mod", "module lib\n")
+	writeFile(t, base, "workspace/lib/pkg/pkg.go", "package pkg\n")
+	writeFile(t, base, ".loglinter.yaml", "rules:\n  english: false\n")
+	writeFile(t, base, "standalone/go.mod", "module standalone\n")
+	writeFile(t, base, "standalone/cmd/main.go", "package main\n")
+
+	tests := []struct {
+		name string
+		dir  string
+		want []string
+	}{
+		{
+			"nested module inside workspace",
+			"workspace/svc/internal/db",
+			[]string{"workspace/.loglinter.yaml", "workspace/svc/.loglinter.yaml"},
+		},
+		{
+			"workspace root config only",
+			"workspace/lib/pkg",
+			[]string{"workspace/.loglinter.yaml"},
+		},
+		{
+			"search stops at module root",
+			"standalone/cmd",
+			nil,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := config.Discover(filepath.Join(base, tc.dir))
+			var want []string
+			for _, p := range tc.want {
+				want = append(want, filepath.Join(base, p))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Discover(%s) = %v, want %v", tc.dir, got, want)
+			}
+		})
+	}
+}
+
+func TestResolve_MergesNestedConfigs(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	writeFile(t, base, "go.mod", "module m\n")
+	writeFile(t, base, ".loglinter.yaml", `
+rules:
+  special: false
+sensitive_keywords: [parent_secret]
+allowed_special_chars: "!"
+`)
+	writeFile(t, base, "sub/.loglinter.yaml", `
+rules:
+  special: true
+  lowercase: false
+sensitive_keywords: [child_secret]
+`)
+
+	cfg, err := config.Resolve(filepath.Join(base, "sub"))
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if !cfg.IsRuleEnabled(config.RuleSpecial) {
+		t.Error("child config should re-enable the special rule")
+	}
+	if cfg.IsRuleEnabled(config.RuleLowercase) {
+		t.Error("child config should disable the lowercase rule")
+	}
+	if cfg.AllowedSpecialChars != "!" {
+		t.Errorf("AllowedSpecialChars = %q, want inherited %q", cfg.AllowedSpecialChars, "!")
+	}
+	for _, kw := range []string{"password", "parent_secret", "child_secret"} {
+		found := false
+		for _, have := range cfg.SensitiveKeywords {
+			if have == kw {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected keyword %q in %v", kw, cfg.SensitiveKeywords)
+		}
+	}
+}
+
+func TestResolve_InvalidNestedConfig(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	writeFile(t, base, "go.mod", "module m\n")
+	writeFile(t, base, "sub/.loglinter.yaml", "rules:\n  lowercsae: false\n")
+
+	if _, err := config.Resolve(filepath.Join(base, "sub")); err == nil {
+		t.Error("expected error from invalid nested config")
+	}
+}
+
+func writeFile(t *testing.T, base, rel, content string) {
+	t.Helper()
+	path := filepath.Join(base, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("writeFile: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writeFile: %v", err)
+	}
+}
@@ -0,0 +1,522 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Wladim1r/loglinter/internal/rules"
+)
+
+// Error describes a problem in a configuration file. Line and Column are
+// 1-based; a zero value means the position is unknown.
+type Error struct {
+	Path   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("loglinter: config %s:%d:%d: %s", e.Path, e.Line, e.Column, e.Msg)
+	case e.Line > 0:
+		return fmt.Sprintf("loglinter: config %s:%d: %s", e.Path, e.Line, e.Msg)
+	default:
+		return fmt.Sprintf("loglinter: config %s: %s", e.Path, e.Msg)
+	}
+}
+
+// nodeError builds an *Error positioned at node n.
+func nodeError(path string, n *yaml.Node, format string, args ...interface{}) *Error {
+	return &Error{
+		Path:   path,
+		Line:   n.Line,
+		Column: n.Column,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+// yamlLineRe extracts the line number yaml.v3 embeds in its error strings,
+// e.g. "yaml: line 3: mapping values are not allowed in this context".
+var yamlLineRe = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlError converts an error returned by yaml.v3 into a positioned *Error.
+func yamlError(path string, err error) error {
+	msg := err.Error()
+
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		// Report the first problem; fixing it usually reveals the next one.
+		msg = typeErr.Errors[0]
+	}
+
+	e := &Error{Path: path, Msg: strings.TrimPrefix(msg, "yaml: ")}
+	if m := yamlLineRe.FindStringSubmatch(msg); m != nil {
+		e.Line, _ = strconv.Atoi(m[1])
+		e.Msg = m[2]
+	}
+	return e
+}
+
+// validate walks the document tree and reports the first semantic error:
+// unknown keys, unknown rule names or invalid special characters.
+func validate(path string, root *yaml.Node) error {
+	if root.Kind != yaml.MappingNode {
+		return nodeError(path, root, "top-level value must be a mapping of settings")
+	}
+	if err := checkKeys(path, root, reflect.TypeOf(fileConfig{})); err != nil {
+		return err
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "rules":
+			if err := checkRuleNames(path, value); err != nil {
+				return err
+			}
+		case "sensitive_keywords_mode":
+			if value.Kind == yaml.ScalarNode &&
+				value.Value != KeywordsExtend && value.Value != KeywordsReplace {
+				return nodeError(path, value, "sensitive_keywords_mode: invalid value %q (want %q or %q)",
+					value.Value, KeywordsExtend, KeywordsReplace)
+			}
+		case "sensitive_keyword_match":
+			if err := checkMatchModes(path, value); err != nil {
+				return err
+			}
+		case "pii_detectors":
+			if err := checkNames(path, "pii_detectors", "detector", value, rules.PIIDetectorNames()); err != nil {
+				return err
+			}
+		case "secrets":
+			if err := checkSecrets(path, value); err != nil {
+				return err
+			}
+		case "length":
+			if err := checkLength(path, value); err != nil {
+				return err
+			}
+		case "eventid":
+			if err := checkEventID(path, value); err != nil {
+				return err
+			}
+		case "duplicate":
+			if err := checkDuplicate(path, value); err != nil {
+				return err
+			}
+		case "transliteration":
+			if err := checkTransliteration(path, value); err != nil {
+				return err
+			}
+		case "redaction":
+			if err := checkRedaction(path, value); err != nil {
+				return err
+			}
+		case "allowed_scripts":
+			if err := checkScripts(path, value); err != nil {
+				return err
+			}
+		case "allowed_runes":
+			if err := checkRunes(path, value); err != nil {
+				return err
+			}
+		case "allowed_special_chars":
+			if err := checkSpecialChars(path, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkKeys verifies that every key of a mapping node corresponds to a yaml
+// tag of the struct type t, recursing into nested structs and slices.
+func checkKeys(path string, n *yaml.Node, t reflect.Type) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		if t.Kind() == reflect.Slice && n.Kind == yaml.SequenceNode {
+			for _, item := range n.Content {
+				if err := checkKeys(path, item, t.Elem()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || n.Kind != yaml.MappingNode {
+		// Type mismatches are left to the strict decoder.
+		return nil
+	}
+
+	fields := make(map[string]reflect.Type, t.NumField())
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = f.Type
+		names = append(names, name)
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i]
+		ft, ok := fields[key.Value]
+		if !ok {
+			return nodeError(path, key, "unknown key %q%s", key.Value, didYouMean(key.Value, names))
+		}
+		if err := checkKeys(path, n.Content[i+1], ft); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRuleNames rejects entries of the rules mapping that do not name a
+// known rule, so that a typo cannot silently leave a rule enabled.
+func checkRuleNames(path string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	known := RuleNames()
+	for i := 0; i < len(n.Content); i += 2 {
+		key := n.Content[i]
+		if _, ok := defaultRules[key.Value]; !ok {
+			return nodeError(path, key, "unknown rule %q%s (known rules: %s)",
+				key.Value, didYouMean(key.Value, known), strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// checkMatchModes rejects values of the sensitive_keyword_match mapping that
+// are not a known match mode.
+func checkMatchModes(path string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 1; i < len(n.Content); i += 2 {
+		value := n.Content[i]
+		if value.Kind != yaml.ScalarNode {
+			continue
+		}
+		if _, ok := rules.ParseMatchMode(value.Value); !ok {
+			return nodeError(path, value,
+				"sensitive_keyword_match: invalid mode %q for keyword %q (want segment, prefix or substring)",
+				value.Value, n.Content[i-1].Value)
+		}
+	}
+	return nil
+}
+
+// checkNames rejects entries of the sequence n that are not in known.
+func checkNames(path, key, what string, n *yaml.Node, known []string) error {
+	if n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, item := range n.Content {
+		if item.Kind == yaml.ScalarNode && !slices.Contains(known, item.Value) {
+			return nodeError(path, item, "%s: unknown %s %q%s (known: %s)",
+				key, what, item.Value, didYouMean(item.Value, known), strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// checkSecrets validates the secrets section: detectors need a unique name
+// and a pattern that compiles, thresholds must be in range and disabled
+// detectors must be built in or defined in the same file.
+func checkSecrets(path string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	var known []string
+	for _, d := range rules.BuiltinSecretDetectors() {
+		known = append(known, d.Name)
+	}
+
+	var disable *yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		switch key.Value {
+		case "entropy_threshold":
+			f, err := strconv.ParseFloat(value.Value, 64)
+			if value.Kind == yaml.ScalarNode && err == nil && (f < 0 || f > 8) {
+				return nodeError(path, value, "secrets.entropy_threshold: %v is out of range (want 0 to 8 bits per character)", f)
+			}
+		case "min_length":
+			l, err := strconv.Atoi(value.Value)
+			if value.Kind == yaml.ScalarNode && err == nil && l < 1 {
+				return nodeError(path, value, "secrets.min_length: must be positive, got %d", l)
+			}
+		case "detectors":
+			if value.Kind != yaml.SequenceNode {
+				continue
+			}
+			seen := make(map[string]bool)
+			for _, item := range value.Content {
+				name, err := checkSecretDetector(path, item)
+				if err != nil {
+					return err
+				}
+				if seen[name] {
+					return nodeError(path, item, "secrets.detectors: duplicate detector %q", name)
+				}
+				seen[name] = true
+				if !slices.Contains(known, name) {
+					known = append(known, name)
+				}
+			}
+		case "disable":
+			disable = value
+		}
+	}
+	if disable != nil {
+		return checkNames(path, "secrets.disable", "detector", disable, known)
+	}
+	return nil
+}
+
+// checkSecretDetector validates a single entry of secrets.detectors and
+// returns its name.
+func checkSecretDetector(path string, n *yaml.Node) (string, error) {
+	if n.Kind != yaml.MappingNode {
+		return "", nil
+	}
+	var name, pattern *yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		switch n.Content[i].Value {
+		case "name":
+			name = n.Content[i+1]
+		case "pattern":
+			pattern = n.Content[i+1]
+		}
+	}
+	if name == nil || name.Value == "" {
+		return "", nodeError(path, n, "secrets.detectors: detector without a name")
+	}
+	if pattern == nil || pattern.Value == "" {
+		return "", nodeError(path, n, "secrets.detectors: detector %q has no pattern", name.Value)
+	}
+	if _, err := regexp.Compile(pattern.Value); err != nil {
+		return "", nodeError(path, pattern, "secrets.detectors: invalid pattern for %q: %v", name.Value, err)
+	}
+	return name.Value, nil
+}
+
+// checkLength rejects negative bounds and a minimum above the maximum.
+func checkLength(path string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	bounds := map[string]int{}
+	var maxNode *yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		v, err := strconv.Atoi(value.Value)
+		if value.Kind != yaml.ScalarNode || err != nil {
+			// Type mismatches are left to the strict decoder.
+			continue
+		}
+		if v < 0 {
+			return nodeError(path, value, "length.%s: must not be negative, got %d", key.Value, v)
+		}
+		bounds[key.Value] = v
+		if key.Value == "max_length" {
+			maxNode = value
+		}
+	}
+	minLen, maxLen := bounds["min_length"], bounds["max_length"]
+	if maxNode != nil && maxLen > 0 && minLen > maxLen {
+		return nodeError(path, maxNode, "length.max_length: %d is below min_length %d", maxLen, minLen)
+	}
+	return nil
+}
+
+// checkDuplicate rejects a max_call_sites below one, which would report
+// every message.
+func checkDuplicate(path string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.Value != "max_call_sites" || value.Kind != yaml.ScalarNode {
+			continue
+		}
+		// Type mismatches are left to the strict decoder.
+		if v, err := strconv.Atoi(value.Value); err == nil && v < 1 {
+			return nodeError(path, value, "duplicate.max_call_sites: must be at least 1, got %d", v)
+		}
+	}
+	return nil
+}
+
+// checkEventID validates the eventid section: the key must not be empty,
+// levels must be known and the pattern must compile.
+func checkEventID(path string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		switch key.Value {
+		case "key":
+			if value.Kind == yaml.ScalarNode && strings.TrimSpace(value.Value) == "" {
+				return nodeError(path, value, "eventid.key: must not be empty")
+			}
+		case "levels":
+			if err := checkNames(path, "eventid.levels", "level", value, rules.LogLevelNames()); err != nil {
+				return err
+			}
+		case "pattern":
+			if value.Kind != yaml.ScalarNode {
+				continue
+			}
+			if _, err := regexp.Compile(value.Value); err != nil {
+				return nodeError(path, value, "eventid.pattern: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkTransliteration validates the transliteration section: tables must
+// be built in, custom keys must be single characters and their spelling must
+// be ASCII, or the fix would not satisfy the english rule.
+func checkTransliteration(path string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		switch key.Value {
+		case "tables":
+			if err := checkNames(path, "transliteration.tables", "table", value, rules.TransliterationTableNames()); err != nil {
+				return err
+			}
+		case "custom":
+			if value.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				from, to := value.Content[j], value.Content[j+1]
+				if utf8.RuneCountInString(from.Value) != 1 {
+					return nodeError(path, from, "transliteration.custom: key %q must be a single character", from.Value)
+				}
+				for _, r := range to.Value {
+					if r > unicode.MaxASCII {
+						return nodeError(path, to, "transliteration.custom: spelling %q of %q must be ASCII", to.Value, from.Value)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkRedaction validates the redaction section: the package must look
+// like an import path and the function must be an exported identifier, and
+// a file that sets one must set both.
+func checkRedaction(path string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	set := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if value.Kind != yaml.ScalarNode {
+			continue
+		}
+		set[key.Value] = true
+		switch key.Value {
+		case "package":
+			if value.Value == "" || strings.ContainsAny(value.Value, " \t\"\\`") ||
+				strings.HasPrefix(value.Value, "/") || strings.HasSuffix(value.Value, "/") {
+				return nodeError(path, value, "redaction.package: %q is not an import path", value.Value)
+			}
+		case "function":
+			if !token.IsIdentifier(value.Value) || !token.IsExported(value.Value) {
+				return nodeError(path, value, "redaction.function: %q is not an exported function name", value.Value)
+			}
+		}
+	}
+	if set["package"] != set["function"] {
+		return nodeError(path, n, "redaction: package and function must be set together")
+	}
+	return nil
+}
+
+// checkScripts rejects names that are not Unicode scripts of the unicode
+// package. Unlike checkNames it does not list the known names, which run to
+// well over a hundred.
+func checkScripts(path string, n *yaml.Node) error {
+	if n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	known := rules.ScriptNames()
+	for _, item := range n.Content {
+		if item.Kind == yaml.ScalarNode && !slices.Contains(known, item.Value) {
+			return nodeError(path, item, "allowed_scripts: unknown script %q%s", item.Value, didYouMean(item.Value, known))
+		}
+	}
+	return nil
+}
+
+// checkRunes rejects allowed_runes entries that are not single characters.
+func checkRunes(path string, n *yaml.Node) error {
+	if n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, item := range n.Content {
+		if item.Kind == yaml.ScalarNode && utf8.RuneCountInString(item.Value) != 1 {
+			return nodeError(path, item, "allowed_runes: %q must be a single character", item.Value)
+		}
+	}
+	return nil
+}
+
+// checkSpecialChars rejects characters that can never be flagged by the
+// special rule: letters, digits, whitespace and non-ASCII runes.
+func checkSpecialChars(path string, n *yaml.Node) error {
+	if n.Kind != yaml.ScalarNode {
+		return nil
+	}
+	for i, r := range []rune(n.Value) {
+		if r <= unicode.MaxASCII && (unicode.IsPunct(r) || unicode.IsSymbol(r)) {
+			continue
+		}
+		return nodeError(path, n,
+			"allowed_special_chars: invalid character %q at index %d (only ASCII punctuation and symbols are allowed)",
+			r, i)
+	}
+	return nil
+}
+
+// didYouMean returns a " (did you mean ...?)" hint for the candidate closest
+// to s, or "" when nothing is reasonably close.
+func didYouMean(s string, candidates []string) string {
+	best, bestDist := "", len(s)/2+1
+	for _, c := range candidates {
+		if d := rules.EditDistance(s, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
@@ -0,0 +1,102 @@
+package rules
+
+// matcher is an Aho-Corasick automaton that finds every occurrence of a fixed
+// set of byte patterns in a single left-to-right scan of the input, so the
+// cost of a search does not grow with the number of patterns.
+type matcher struct {
+	nodes []acNode
+	// lens[id] is the byte length of pattern id.
+	lens []int
+}
+
+type acNode struct {
+	next map[byte]int32
+	fail int32
+	// out lists the ids of all patterns ending at this node, including those
+	// reachable through the failure chain.
+	out []int32
+}
+
+// newMatcher builds an automaton for patterns; pattern ids are their indices.
+// Empty patterns never match.
+func newMatcher(patterns []string) *matcher {
+	m := &matcher{
+		nodes: []acNode{{}},
+		lens:  make([]int, len(patterns)),
+	}
+
+	// Build the trie.
+	for id, p := range patterns {
+		m.lens[id] = len(p)
+		if p == "" {
+			continue
+		}
+		cur := int32(0)
+		for i := 0; i < len(p); i++ {
+			nxt, ok := m.nodes[cur].next[p[i]]
+			if !ok {
+				nxt = int32(len(m.nodes))
+				m.nodes = append(m.nodes, acNode{})
+				if m.nodes[cur].next == nil {
+					m.nodes[cur].next = make(map[byte]int32)
+				}
+				m.nodes[cur].next[p[i]] = nxt
+			}
+			cur = nxt
+		}
+		m.nodes[cur].out = append(m.nodes[cur].out, int32(id))
+	}
+
+	// Compute failure links breadth-first so that a node's failure target is
+	// always complete before the node itself is processed.
+	queue := make([]int32, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for b, child := range m.nodes[cur].next {
+			queue = append(queue, child)
+			f := m.nodes[cur].fail
+			for {
+				if nxt, ok := m.nodes[f].next[b]; ok && nxt != child {
+					m.nodes[child].fail = nxt
+					break
+				}
+				if f == 0 {
+					break
+				}
+				f = m.nodes[f].fail
+			}
+			fail := m.nodes[child].fail
+			m.nodes[child].out = append(m.nodes[child].out, m.nodes[fail].out...)
+		}
+	}
+	return m
+}
+
+// scan reports every match in s as the half-open byte range [start, end) and
+// the pattern id, in order of increasing end offset. Scanning stops early when
+// fn returns false.
+func (m *matcher) scan(s string, fn func(start, end int, id int32) bool) {
+	cur := int32(0)
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		for {
+			if nxt, ok := m.nodes[cur].next[b]; ok {
+				cur = nxt
+				break
+			}
+			if cur == 0 {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		for _, id := range m.nodes[cur].out {
+			if !fn(i+1-m.lens[id], i+1, id) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcher_Scan(t *testing.T) {
+	t.Parallel()
+
+	m := newMatcher([]string{"he", "she", "his", "hers", ""})
+
+	type match struct {
+		start, end int
+		pattern    string
+	}
+	patterns := []string{"he", "she", "his", "hers", ""}
+
+	var got []match
+	m.scan("ushers", func(start, end int, id int32) bool {
+		got = append(got, match{start, end, patterns[id]})
+		return true
+	})
+
+	want := []match{
+		{1, 4, "she"},
+		{2, 4, "he"},
+		{2, 6, "hers"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scan(ushers) = %v, want %v", got, want)
+	}
+}
+
+func TestMatcher_ScanStopsEarly(t *testing.T) {
+	t.Parallel()
+
+	m := newMatcher([]string{"a"})
+	calls := 0
+	m.scan("aaaa", func(_, _ int, _ int32) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("expected scan to stop after the first match, got %d calls", calls)
+	}
+}
@@ -0,0 +1,47 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxCallSites is the default number of call sites a constant
+// message may have before the duplicate rule reports it.
+const DefaultMaxCallSites = 1
+
+// CheckDuplicate reports a constant message used at more than maxCallSites
+// call sites. sites lists every call site, typically as "path:line", and is
+// included in the diagnostic. Messages shared by many call sites, such as
+// "request failed", make it impossible to tell from an alert or a log search
+// which code path produced an entry.
+func CheckDuplicate(msg string, sites []string, maxCallSites int) string {
+	return NewDuplicate(maxCallSites, nil).Check(msg, sites)
+}
+
+// Duplicate is a precompiled form of the duplicate rule. It is immutable
+// after construction and safe for concurrent use.
+type Duplicate struct {
+	max     int
+	allowed map[string]bool
+}
+
+// NewDuplicate compiles the duplicate rule. Messages in allowed are shared on
+// purpose and never reported.
+func NewDuplicate(maxCallSites int, allowed []string) *Duplicate {
+	d := &Duplicate{max: maxCallSites, allowed: make(map[string]bool, len(allowed))}
+	for _, msg := range allowed {
+		d.allowed[msg] = true
+	}
+	return d
+}
+
+// Check runs the rule against a message and all of its call sites; see
+// CheckDuplicate.
+func (d *Duplicate) Check(msg string, sites []string) string {
+	if msg == "" || len(sites) <= d.max || d.allowed[msg] {
+		return ""
+	}
+	return fmt.Sprintf("log message %q is used at %d call sites (maximum %d): %s; "+
+		"make each message specific so alerts and searches can tell them apart",
+		msg, len(sites), d.max, strings.Join(sites, ", "))
+}
@@ -2,66 +2,156 @@ package rules
 
 import (
 	"fmt"
+	"slices"
 	"unicode"
+	"unicode/utf8"
 )
 
-// CheckEnglish verifies that a log message contains only characters from the
-// Latin / ASCII repertoire (letters, digits, whitespace, common punctuation).
+// DefaultAllowedScripts returns the Unicode scripts the english rule allows
+// by default: Latin, so that accented names such as "café" or "Zürich" pass.
+func DefaultAllowedScripts() []string {
+	return []string{"Latin"}
+}
+
+// defaultAllowedRunes are non-ASCII runes of the Common script that are
+// acceptable in English-language technical log messages.
+var defaultAllowedRunes = []rune{
+	'–',      // en-dash
+	'—',      // em-dash
+	'‘', '’', // curly single quotes
+	'“', '”', // curly double quotes
+	'…', // ellipsis
+}
+
+// ScriptNames returns the names of the Unicode scripts known to the english
+// rule, as used by the unicode package ("Cyrillic", "Han", ...), in sorted
+// order.
+func ScriptNames() []string {
+	names := make([]string, 0, len(unicode.Scripts))
+	for name := range unicode.Scripts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// scriptNames caches ScriptNames for scriptOf.
+var scriptNames = ScriptNames()
+
+// CheckEnglish verifies that a log message contains only ASCII characters,
+// letters of the default allowed scripts and a few typographic runes such as
+// dashes and curly quotes.
 //
-// Non-Latin scripts such as Cyrillic, Chinese, Arabic etc. are rejected.
-// This keeps log output machine-parseable and avoids encoding issues when
-// logs are shipped to external aggregators that may not be UTF-8-aware.
+// Other scripts such as Cyrillic, Han or Arabic are rejected, as are emoji
+// and other symbols. This keeps log output searchable and avoids encoding
+// issues when logs are shipped to aggregators that are not UTF-8-aware.
 func CheckEnglish(msg string) string {
-	for _, r := range msg {
-		// Allow ASCII printable characters – letters, digits, punctuation,
-		// whitespace – everything in the Bahttps://gitlab.megotours.kz/mego.tours/backend_v2/core/-/merge_requests/54sic Latin block (U+0000–U+007F).
-		if r <= unicode.MaxASCII {
-			continue
-		}
+	return defaultEnglish.Check(msg)
+}
+
+// defaultEnglish backs CheckEnglish.
+var defaultEnglish, _ = NewEnglish(DefaultAllowedScripts(), nil)
+
+// English is a precompiled form of the english rule. It is immutable after
+// construction and safe for concurrent use.
+type English struct {
+	scripts []*unicode.RangeTable
+	runes   map[rune]bool
+}
 
-		// Allow a small subset of common Latin-Extended characters used in
-		// English technical writing (e.g. en-dash U+2013, ellipsis U+2026).
-		// Everything else is considered non-English.
-		if isAllowedNonASCII(r) {
-			continue
+// NewEnglish compiles the english rule. ASCII is always allowed; scripts
+// names further Unicode scripts whose characters are allowed and runes lists
+// single extra characters, such as "€". It fails for unknown script names.
+func NewEnglish(scripts []string, runes []rune) (*English, error) {
+	e := &English{runes: make(map[rune]bool, len(defaultAllowedRunes)+len(runes))}
+	for _, name := range scripts {
+		table, ok := unicode.Scripts[name]
+		if !ok {
+			return nil, fmt.Errorf("english: unknown script %q", name)
 		}
+		e.scripts = append(e.scripts, table)
+	}
+	for _, r := range defaultAllowedRunes {
+		e.runes[r] = true
+	}
+	for _, r := range runes {
+		e.runes[r] = true
+	}
+	return e, nil
+}
+
+// Check runs the rule against a message and reports the first offending
+// rune with its script and its 1-based column, counted in runes.
+func (e *English) Check(msg string) string {
+	return e.Find(msg).Message
+}
 
-		// Collect the script name for a more helpful diagnostic message.
-		script := scriptName(r)
-		return fmt.Sprintf("log message contains non-English characters (%s script, rune %q)", script, r)
+// Find runs the rule against a message like Check and locates the first
+// offending rune.
+func (e *English) Find(msg string) Finding {
+	offset := e.Offending(msg)
+	if offset < 0 {
+		return noFinding
+	}
+	r, size := utf8.DecodeRuneInString(msg[offset:])
+	return Finding{
+		Message: fmt.Sprintf("log message contains non-English characters (%s script, rune %q at column %d)",
+			scriptOf(r), r, utf8.RuneCountInString(msg[:offset])+1),
+		Start: offset,
+		End:   offset + size,
 	}
-	return ""
 }
 
-// isAllowedNonASCII returns true for a small set of non-ASCII runes that are
-// acceptable in English-language technical log messages.
-func isAllowedNonASCII(r rune) bool {
-	switch r {
-	case '\u2013', // en-dash
-		'\u2014',           // em-dash
-		'\u2018', '\u2019', // curly single quotes
-		'\u201C', '\u201D', // curly double quotes
-		'\u2026': // ellipsis
+// Offending returns the byte offset of the first rune of msg the rule does
+// not allow, or -1 when the message is fine.
+func (e *English) Offending(msg string) int {
+	var prev rune
+	for i, r := range msg {
+		if !e.allowed(r, prev) {
+			return i
+		}
+		prev = r
+	}
+	return -1
+}
+
+// allowed reports whether r may appear in a message after prev, which is 0
+// at the start. Combining marks of the Inherited script, such as the accent
+// of a decomposed "é", belong to the script of the letter they follow.
+func (e *English) allowed(r, prev rune) bool {
+	switch {
+	case r <= unicode.MaxASCII, e.runes[r]:
 		return true
+	case unicode.Is(unicode.Inherited, r):
+		return prev != 0 && e.inScripts(prev)
+	}
+	return e.inScripts(r)
+}
+
+// inScripts reports whether r belongs to one of the allowed scripts.
+func (e *English) inScripts(r rune) bool {
+	for _, table := range e.scripts {
+		if unicode.Is(table, r) {
+			return true
+		}
 	}
 	return false
 }
 
-// scriptName returns a human-readable Unicode script name for the given rune.
-// This is a best-effort categorisation for diagnostic messages only.
-func scriptName(r rune) string {
+// scriptOf returns the name of the Unicode script of r, "Common" for
+// punctuation, symbols and emoji shared by all scripts, or "Unknown" for
+// unassigned code points.
+func scriptOf(r rune) string {
+	for _, name := range scriptNames {
+		if name != "Common" && name != "Inherited" && unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
 	switch {
-	case r >= 0x0400 && r <= 0x04FF:
-		return "Cyrillic"
-	case r >= 0x4E00 && r <= 0x9FFF:
-		return "CJK"
-	case r >= 0x0600 && r <= 0x06FF:
-		return "Arabic"
-	case r >= 0x0900 && r <= 0x097F:
-		return "Devanagari"
-	case r >= 0x1F300 && r <= 0x1FAFF:
-		return "Emoji"
-	default:
-		return "non-Latin"
+	case unicode.Is(unicode.Common, r):
+		return "Common"
+	case unicode.Is(unicode.Inherited, r):
+		return "Inherited"
 	}
+	return "Unknown"
 }
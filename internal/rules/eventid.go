@@ -0,0 +1,102 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Defaults of the eventid rule.
+const (
+	DefaultEventIDKey     = "event_id"
+	DefaultEventIDPattern = `^[a-z0-9]+([._-][a-z0-9]+)*$`
+)
+
+// DefaultEventIDLevels returns the levels that must carry an event ID by
+// default.
+func DefaultEventIDLevels() []string {
+	return []string{"warn", "error"}
+}
+
+// LogLevelNames returns the level names understood by the eventid rule, from
+// the least to the most severe.
+func LogLevelNames() []string {
+	return []string{"debug", "info", "warn", "error", "panic", "fatal"}
+}
+
+// maxEventIDWords is the number of message words used by generated IDs.
+const maxEventIDWords = 4
+
+// EventID is a precompiled form of the eventid rule, which asks log calls to
+// carry a stable, unique event ID attribute so that alerts survive changes to
+// the message text. It is immutable after construction and safe for
+// concurrent use.
+type EventID struct {
+	key     string
+	pattern *regexp.Regexp
+}
+
+// NewEventID compiles the eventid rule for the attribute key. IDs must match
+// pattern; an empty pattern accepts any ID.
+func NewEventID(key, pattern string) (*EventID, error) {
+	e := &EventID{key: key}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("eventid: invalid pattern: %w", err)
+		}
+		e.pattern = re
+	}
+	return e, nil
+}
+
+// Key returns the attribute key that holds the event ID.
+func (e *EventID) Key() string {
+	return e.key
+}
+
+// CheckValue verifies an event ID against the configured pattern.
+func (e *EventID) CheckValue(id string) string {
+	if e.pattern != nil && !e.pattern.MatchString(id) {
+		return fmt.Sprintf("event ID %q does not match the pattern %s", id, e.pattern)
+	}
+	return ""
+}
+
+// CheckUnique reports an event ID that is used at more than one call site.
+// sites lists every call site, typically as "path:line".
+func (e *EventID) CheckUnique(id string, sites []string) string {
+	if len(sites) <= 1 {
+		return ""
+	}
+	return fmt.Sprintf("event ID %q is used at %d call sites: %s; each event needs its own ID",
+		id, len(sites), strings.Join(sites, ", "))
+}
+
+// Generate derives a new event ID from a prefix, usually the package name,
+// and the first words of the message: "orders" and "Request failed"
+// give "orders.request_failed". taken reports IDs already in use; a numeric
+// suffix makes the ID unique. It returns "" when the derived ID does not
+// match the pattern.
+func (e *EventID) Generate(prefix, msg string, taken func(string) bool) string {
+	words := tokenizeWords(strings.ToLower(msg))
+	if len(words) > maxEventIDWords {
+		words = words[:maxEventIDWords]
+	}
+	base := strings.Join(words, "_")
+	if base == "" {
+		base = "event"
+	}
+	if prefix != "" {
+		base = strings.ToLower(prefix) + "." + base
+	}
+
+	id := base
+	for n := 2; taken(id); n++ {
+		id = fmt.Sprintf("%s_%d", base, n)
+	}
+	if e.CheckValue(id) != "" {
+		return ""
+	}
+	return id
+}
@@ -0,0 +1,21 @@
+package rules
+
+// Finding is a rule violation together with the text that causes it, so that
+// diagnostics can point at the exact rune, keyword or identifier instead of
+// the whole message.
+type Finding struct {
+	// Message is the diagnostic; it is empty when the text is fine.
+	Message string
+	// Start and End are the byte range of the offending text within the
+	// checked string. Both are -1 when the violation is not in the string,
+	// for example a keyword found in the argument expression.
+	Start, End int
+	// Keyword is the keyword found by the sensitive rule, and Ident the
+	// identifier of the argument expression that holds it, when the rule
+	// found it there.
+	Keyword string
+	Ident   string
+}
+
+// noFinding is the Finding of text that passes a rule.
+var noFinding = Finding{Start: -1, End: -1}
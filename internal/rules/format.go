@@ -0,0 +1,252 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Names of the sub-checks of the format rule.
+const (
+	FormatTrailingPeriod = "trailing_period"
+	FormatWhitespace     = "whitespace"
+	FormatControlChars   = "control_chars"
+	FormatDoubleSpaces   = "double_spaces"
+)
+
+// FormatOptions selects the sub-checks run by the format rule.
+type FormatOptions struct {
+	// TrailingPeriod flags messages ending with a single period.
+	TrailingPeriod bool
+	// Whitespace flags leading and trailing whitespace.
+	Whitespace bool
+	// ControlChars flags newlines, carriage returns, tabs and other control
+	// characters, which break line-based log shipping.
+	ControlChars bool
+	// DoubleSpaces flags runs of spaces inside the message.
+	DoubleSpaces bool
+}
+
+// AllFormatChecks enables every sub-check.
+var AllFormatChecks = FormatOptions{
+	TrailingPeriod: true,
+	Whitespace:     true,
+	ControlChars:   true,
+	DoubleSpaces:   true,
+}
+
+// FormatPart is one operand of a message built by concatenation. Only
+// constant parts are checked; the position of dynamic parts decides whether
+// a constant part starts or ends the message.
+type FormatPart struct {
+	Text  string
+	Const bool
+}
+
+// FormatIssue is a violation of one sub-check in one part of the message.
+type FormatIssue struct {
+	// Check is the name of the violated sub-check.
+	Check   string
+	Message string
+	// Part is the index of the offending part and Fixed its corrected text.
+	Part  int
+	Fixed string
+	// Start and End are the byte range of the offending text within the
+	// part.
+	Start, End int
+}
+
+// CheckFormat verifies the formatting of a constant log message with every
+// sub-check enabled: no trailing period, no leading or trailing whitespace,
+// no control characters and no double spaces. It returns the first
+// violation.
+func CheckFormat(msg string) string {
+	issues := NewFormat(AllFormatChecks).Check([]FormatPart{{Text: msg, Const: true}})
+	if len(issues) == 0 {
+		return ""
+	}
+	return issues[0].Message
+}
+
+// Format is a precompiled form of the format rule. It is immutable after
+// construction and safe for concurrent use.
+type Format struct {
+	opts FormatOptions
+}
+
+// NewFormat compiles the format rule for the given sub-checks.
+func NewFormat(opts FormatOptions) *Format {
+	return &Format{opts: opts}
+}
+
+// Check runs the enabled sub-checks against the parts of a message and
+// returns every violation, at most one per sub-check and part.
+func (f *Format) Check(parts []FormatPart) []FormatIssue {
+	var issues []FormatIssue
+	last := len(parts) - 1
+
+	for i, p := range parts {
+		if !p.Const {
+			continue
+		}
+		if f.opts.ControlChars {
+			if r, at := firstControl(p.Text); at >= 0 {
+				issues = append(issues, FormatIssue{
+					Check:   FormatControlChars,
+					Message: fmt.Sprintf("log message contains control character %q, which breaks line-based log shipping", r),
+					Part:    i,
+					Fixed:   replaceControls(p.Text, i == 0, i == last),
+					Start:   at,
+					End:     at + utf8.RuneLen(r),
+				})
+			}
+		}
+		if f.opts.Whitespace && i == 0 {
+			if trimmed := strings.TrimLeftFunc(p.Text, isPlainSpace); trimmed != p.Text {
+				issues = append(issues, FormatIssue{
+					Check:   FormatWhitespace,
+					Message: "log message should not start with whitespace",
+					Part:    i,
+					Fixed:   trimmed,
+					Start:   0,
+					End:     len(p.Text) - len(trimmed),
+				})
+			}
+		}
+		if f.opts.Whitespace && i == last {
+			if trimmed := strings.TrimRightFunc(p.Text, isPlainSpace); trimmed != p.Text {
+				issues = append(issues, FormatIssue{
+					Check:   FormatWhitespace,
+					Message: "log message should not end with whitespace",
+					Part:    i,
+					Fixed:   trimmed,
+					Start:   len(trimmed),
+					End:     len(p.Text),
+				})
+			}
+		}
+		if f.opts.TrailingPeriod && i == last {
+			trimmed := strings.TrimRightFunc(p.Text, unicode.IsSpace)
+			if strings.HasSuffix(trimmed, ".") && !strings.HasSuffix(trimmed, "..") {
+				cut := len(trimmed) - 1
+				issues = append(issues, FormatIssue{
+					Check:   FormatTrailingPeriod,
+					Message: "log message should not end with a period",
+					Part:    i,
+					Fixed:   p.Text[:cut] + p.Text[cut+1:],
+					Start:   cut,
+					End:     cut + 1,
+				})
+			}
+		}
+		if f.opts.DoubleSpaces {
+			if fixed := collapseSpaces(p.Text, i == 0, i == last); fixed != p.Text {
+				start, end := repeatedSpaces(p.Text, i == 0)
+				issues = append(issues, FormatIssue{
+					Check:   FormatDoubleSpaces,
+					Message: "log message contains repeated spaces",
+					Part:    i,
+					Fixed:   fixed,
+					Start:   start,
+					End:     end,
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// isPlainSpace reports whether r is whitespace other than a control
+// character; control characters are reported by their own sub-check.
+func isPlainSpace(r rune) bool {
+	return unicode.IsSpace(r) && !unicode.IsControl(r)
+}
+
+// firstControl returns the first control character of s and its byte
+// offset, or -1 when there is none.
+func firstControl(s string) (rune, int) {
+	for i, r := range s {
+		if unicode.IsControl(r) {
+			return r, i
+		}
+	}
+	return 0, -1
+}
+
+// replaceControls replaces every run of control characters, together with
+// the spaces around it, by a single space. Runs at the start of the first part
+// or at the end of the last part of a message are removed instead.
+func replaceControls(s string, first, last bool) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	pending := false
+	for _, r := range s {
+		switch {
+		case unicode.IsControl(r):
+			if !pending {
+				trimmed := strings.TrimRight(b.String(), " ")
+				b.Reset()
+				b.WriteString(trimmed)
+				pending = true
+			}
+		case r == ' ' && pending:
+			// Swallowed by the pending separator.
+		default:
+			if pending && (b.Len() > 0 || !first) {
+				b.WriteByte(' ')
+			}
+			pending = false
+			b.WriteRune(r)
+		}
+	}
+	if pending && !last {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// repeatedSpaces returns the byte range of the first run of two or more
+// spaces in s, skipping leading spaces of the first part like collapseSpaces.
+func repeatedSpaces(s string, first bool) (int, int) {
+	from := 0
+	if first {
+		from = len(s) - len(strings.TrimLeft(s, " "))
+	}
+	start := strings.Index(s[from:], "  ")
+	if start < 0 {
+		return -1, -1
+	}
+	start += from
+	end := start + 2
+	for end < len(s) && s[end] == ' ' {
+		end++
+	}
+	return start, end
+}
+
+// collapseSpaces replaces runs of two or more spaces by a single space.
+// Runs at the start of the first part or at the end of the last part of a
+// message are left to the whitespace sub-check.
+func collapseSpaces(s string, first, last bool) string {
+	start, end := 0, len(s)
+	if first {
+		start = len(s) - len(strings.TrimLeft(s, " "))
+	}
+	if last {
+		end = len(strings.TrimRight(s, " "))
+	}
+	if start >= end || !strings.Contains(s[start:end], "  ") {
+		return s
+	}
+	var b strings.Builder
+	b.WriteString(s[:start])
+	for i := start; i < end; i++ {
+		if s[i] == ' ' && i > start && s[i-1] == ' ' {
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteString(s[end:])
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package rules
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Default limits of the length rule, in runes.
+const (
+	DefaultMinMessageLength = 1
+	DefaultMaxMessageLength = 120
+)
+
+// CheckLength verifies that the constant portion of a log message is between
+// min and max runes long; zero disables a limit. When dynamic is true the
+// message also has non-constant operands, so only the maximum is checked.
+//
+// An empty message leaves the message column of log viewers blank, and a very
+// long one usually embeds data such as a JSON payload that belongs in
+// structured attributes.
+func CheckLength(msg string, dynamic bool, min, max int) string {
+	return NewLength(min, max).Check(msg, dynamic)
+}
+
+// Length is a precompiled form of the length rule. It is immutable after
+// construction and safe for concurrent use.
+type Length struct {
+	min, max int
+}
+
+// NewLength compiles the length rule; see CheckLength.
+func NewLength(min, max int) *Length {
+	return &Length{min: min, max: max}
+}
+
+// Check runs the rule against the constant portion of a message; see
+// CheckLength.
+func (l *Length) Check(msg string, dynamic bool) string {
+	n := utf8.RuneCountInString(msg)
+	switch {
+	case !dynamic && n == 0 && l.min > 0:
+		return "log message is empty; describe the event in the message and keep data in attributes"
+	case !dynamic && n < l.min:
+		return fmt.Sprintf("log message is too short (%d runes, minimum %d)", n, l.min)
+	case l.max > 0 && n > l.max:
+		return fmt.Sprintf(
+			"log message is too long (%d runes, maximum %d); keep the message short and move data into structured attributes",
+			n, l.max)
+	}
+	return ""
+}
@@ -0,0 +1,332 @@
+package rules
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Names of the personal-data detectors understood by NewPII.
+const (
+	PIIEmail = "email"
+	PIIIPv4  = "ipv4"
+	PIIIPv6  = "ipv6"
+	PIIPhone = "phone"
+	PIIIBAN  = "iban"
+	PIICard  = "card"
+)
+
+// piiDetector finds candidates with a cheap regular expression and confirms
+// them with a validator, so that version numbers, timestamps or random digit
+// runs are not reported as personal data.
+type piiDetector struct {
+	name  string
+	label string
+	re    *regexp.Regexp
+	valid func(candidate string) bool
+	// token requires the candidate to stand alone: it may not touch a
+	// letter, a digit or ':' on either side, so that fragments of words and
+	// identifiers such as "cache::key" are not taken for addresses. With
+	// port, a ":8080" suffix is allowed.
+	token, port bool
+	// notAfter lists words after which a candidate is not personal data,
+	// such as "version" before a dotted number.
+	notAfter []string
+}
+
+// piiDetectors lists every detector in the order they are tried.
+var piiDetectors = []piiDetector{
+	{
+		name:  PIIEmail,
+		label: "email address",
+		re:    regexp.MustCompile(`[A-Za-z0-9._+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		valid: validEmail,
+	},
+	{
+		name:  PIIIBAN,
+		label: "IBAN",
+		re:    regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		valid: validIBAN,
+	},
+	{
+		name:  PIICard,
+		label: "payment card number",
+		re:    regexp.MustCompile(`\b(?:[0-9][ \-]?){12,18}[0-9]\b`),
+		valid: validCard,
+	},
+	{
+		name:  PIIPhone,
+		label: "phone number",
+		re:    regexp.MustCompile(`\+[0-9][0-9 ().\-]{6,20}[0-9]|\([0-9]{3}\) ?[0-9]{3}-[0-9]{4}`),
+		valid: validPhone,
+	},
+	{
+		name:     PIIIPv4,
+		label:    "IPv4 address",
+		re:       regexp.MustCompile(`[0-9.]*[0-9]+\.[0-9]+\.[0-9]+\.[0-9]+[0-9.]*`),
+		valid:    validIPv4,
+		token:    true,
+		port:     true,
+		notAfter: []string{"v", "version"},
+	},
+	{
+		name:  PIIIPv6,
+		label: "IPv6 address",
+		re:    regexp.MustCompile(`[0-9A-Fa-f:.]*:[0-9A-Fa-f:.]*:[0-9A-Fa-f:.]*`),
+		valid: validIPv6,
+		token: true,
+	},
+}
+
+// PIIDetectorNames returns the names of all personal-data detectors.
+func PIIDetectorNames() []string {
+	names := make([]string, len(piiDetectors))
+	for i, d := range piiDetectors {
+		names[i] = d.name
+	}
+	return names
+}
+
+// CheckPII verifies that text – a resolved log message or a constant
+// argument – does not contain hard-coded personal data such as email
+// addresses, IP addresses, phone numbers, IBANs or payment card numbers.
+//
+// detectors selects the detectors to run by name; nil runs all of them.
+func CheckPII(text string, detectors []string) string {
+	return NewPII(detectors).Check(text)
+}
+
+// PII is a precompiled form of the personal-data rule. It is immutable after
+// construction and safe for concurrent use.
+type PII struct {
+	detectors []*piiDetector
+}
+
+// NewPII compiles the personal-data rule for the named detectors; nil
+// enables all of them. Unknown names are ignored.
+func NewPII(detectors []string) *PII {
+	p := &PII{}
+	for i := range piiDetectors {
+		d := &piiDetectors[i]
+		if detectors == nil || containsFold(detectors, d.name) {
+			p.detectors = append(p.detectors, d)
+		}
+	}
+	return p
+}
+
+// Check runs the rule against text; see CheckPII.
+func (p *PII) Check(text string) string {
+	return p.Find(text).Message
+}
+
+// Find runs the rule against text like Check and locates the personal data.
+func (p *PII) Find(text string) Finding {
+	for _, d := range p.detectors {
+		for _, loc := range d.re.FindAllStringIndex(text, -1) {
+			// "%s@example.com" is a format template, not an address.
+			if loc[0] > 0 && text[loc[0]-1] == '%' {
+				continue
+			}
+			if d.token && !standsAlone(text, loc[0], loc[1], d.port) {
+				continue
+			}
+			if containsFold(d.notAfter, wordBefore(text[:loc[0]])) {
+				continue
+			}
+			if candidate := text[loc[0]:loc[1]]; d.valid(candidate) {
+				return Finding{
+					Message: fmt.Sprintf("log message contains personal data (%s %q)", d.label, candidate),
+					Start:   loc[0],
+					End:     loc[1],
+				}
+			}
+		}
+	}
+	return noFinding
+}
+
+// standsAlone reports whether text[start:end] touches no letter, digit or
+// ':' on either side. With port, a ":" followed by a digit may follow.
+func standsAlone(text string, start, end int, port bool) bool {
+	if start > 0 {
+		if r, _ := utf8.DecodeLastRuneInString(text[:start]); r == ':' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[end:])
+		if r == ':' && port && end+1 < len(text) && text[end+1] >= '0' && text[end+1] <= '9' {
+			return true
+		}
+		if r == ':' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// wordBefore returns the word that ends right before before, skipping
+// spaces, or "" when there is none.
+func wordBefore(before string) string {
+	before = strings.TrimRightFunc(before, unicode.IsSpace)
+	i := strings.LastIndexFunc(before, func(r rune) bool { return !unicode.IsLetter(r) })
+	return before[i+1:]
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// validEmail checks the structure of the local part and of every domain
+// label, rejecting things like "a..b@x.io" or "user@-host.com".
+func validEmail(s string) bool {
+	at := strings.LastIndexByte(s, '@')
+	local, domain := s[:at], s[at+1:]
+	if local == "" || len(local) > 64 || local[0] == '.' || local[len(local)-1] == '.' ||
+		strings.Contains(local, "..") {
+		return false
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, l := range labels {
+		if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// validIPv4 accepts exactly four dotted octets that form a public address.
+func validIPv4(s string) bool {
+	// Allow a sentence-ending dot but not longer dotted runs such as
+	// version numbers ("1.2.3.4.5").
+	addr, err := netip.ParseAddr(strings.TrimSuffix(s, "."))
+	if err != nil || !addr.Is4() {
+		return false
+	}
+	return isPublicAddr(addr)
+}
+
+// validIPv6 accepts any textual IPv6 address that is public. A compressed
+// address without a single decimal digit, such as "Abc::Def", is taken for a
+// scoped identifier rather than an address.
+func validIPv6(s string) bool {
+	if strings.Contains(s, "::") && countDigits(s) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(strings.Trim(s, "."))
+	if err != nil || !addr.Is6() {
+		return false
+	}
+	return isPublicAddr(addr)
+}
+
+// isPublicAddr reports whether addr can identify a host on the internet.
+// Loopback, unspecified, private (RFC 1918 and IPv6 unique local),
+// link-local and multicast addresses are infrastructure, not personal data.
+func isPublicAddr(addr netip.Addr) bool {
+	return !addr.IsLoopback() && !addr.IsUnspecified() && !addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() && !addr.IsMulticast()
+}
+
+// validPhone requires an E.164-sized number (8 to 15 digits) written either in
+// international form with a leading "+" or in the North American
+// "(555) 123-4567" form.
+func validPhone(s string) bool {
+	n := countDigits(s)
+	if strings.HasPrefix(s, "(") {
+		return n == 10
+	}
+	return n >= 8 && n <= 15
+}
+
+// validIBAN checks the length and the ISO 13616 mod-97 checksum.
+func validIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+	// Move the country code and check digits to the end and interpret
+	// letters as numbers (A=10 … Z=35), computing the remainder on the fly.
+	rearranged := s[4:] + s[:4]
+	rem := 0
+	for i := 0; i < len(rearranged); i++ {
+		c := rearranged[i]
+		switch {
+		case c >= '0' && c <= '9':
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
+
+// validCard requires 13 to 19 digits with a known issuer prefix and a valid
+// Luhn checksum.
+func validCard(s string) bool {
+	digits := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = append(digits, s[i])
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 || !knownCardPrefix(string(digits)) {
+		return false
+	}
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// knownCardPrefix reports whether digits start with the issuer identification
+// number of a major card network (Visa, Mastercard, Amex, Discover, JCB,
+// Diners Club, UnionPay).
+func knownCardPrefix(digits string) bool {
+	if digits[0] == '4' {
+		return true
+	}
+	for _, p := range []string{"34", "37", "35", "30", "36", "38", "62", "65", "6011"} {
+		if strings.HasPrefix(digits, p) {
+			return true
+		}
+	}
+	two := int(digits[0]-'0')*10 + int(digits[1]-'0')
+	four := two*100 + int(digits[2]-'0')*10 + int(digits[3]-'0')
+	three := four / 10
+	return (two >= 51 && two <= 55) || // Mastercard
+		(four >= 2221 && four <= 2720) || // Mastercard 2-series
+		(three >= 644 && three <= 649) // Discover
+}
+
+func countDigits(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			n++
+		}
+	}
+	return n
+}
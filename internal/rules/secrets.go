@@ -0,0 +1,222 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SecretDetector describes a known credential format recognised by a regular
+// expression.
+type SecretDetector struct {
+	// Name identifies the detector in diagnostics and configuration.
+	Name string
+	// Pattern is a regular expression (RE2 syntax) matching the credential.
+	Pattern string
+}
+
+// Default thresholds for the high-entropy check.
+const (
+	DefaultEntropyThreshold = 4.0
+	DefaultEntropyMinLength = 20
+)
+
+// builtinSecretDetectors lists the credential formats recognised out of the
+// box.
+var builtinSecretDetectors = []SecretDetector{
+	{Name: "aws_access_key", Pattern: `\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`},
+	{Name: "github_token", Pattern: `\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`},
+	{Name: "jwt", Pattern: `\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*`},
+	{Name: "pem_private_key", Pattern: `-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`},
+	{Name: "slack_token", Pattern: `\bxox[abposr]-[A-Za-z0-9-]{10,}`},
+}
+
+// BuiltinSecretDetectors returns a copy of the built-in credential detectors.
+func BuiltinSecretDetectors() []SecretDetector {
+	return append([]SecretDetector(nil), builtinSecretDetectors...)
+}
+
+// SecretsOptions configures the secrets rule.
+type SecretsOptions struct {
+	// Detectors are the credential formats to look for, usually the built-in
+	// ones plus project-specific additions.
+	Detectors []SecretDetector
+	// EntropyThreshold is the minimum Shannon entropy, in bits per character,
+	// for a token to be reported as a likely secret. Zero disables the check.
+	EntropyThreshold float64
+	// MinLength is the minimum length of a token considered by the entropy
+	// check.
+	MinLength int
+}
+
+// CheckSecrets verifies that text – a resolved log message or a constant
+// argument – does not contain a hard-coded credential: either a known
+// format such as an AWS access key, GitHub or Slack token, JWT or PEM private
+// key, or a long random-looking token.
+//
+// It uses the built-in detectors and default entropy thresholds.
+func CheckSecrets(text string) string {
+	s, _ := NewSecrets(SecretsOptions{
+		Detectors:        builtinSecretDetectors,
+		EntropyThreshold: DefaultEntropyThreshold,
+		MinLength:        DefaultEntropyMinLength,
+	})
+	return s.Check(text)
+}
+
+// Secrets is a precompiled form of the secrets rule. It is immutable after
+// construction and safe for concurrent use.
+type Secrets struct {
+	names     []string
+	patterns  []*regexp.Regexp
+	threshold float64
+	minLength int
+}
+
+// NewSecrets compiles the secrets rule. It fails if a detector pattern is not
+// a valid regular expression.
+func NewSecrets(opts SecretsOptions) (*Secrets, error) {
+	s := &Secrets{
+		threshold: opts.EntropyThreshold,
+		minLength: opts.MinLength,
+	}
+	for _, d := range opts.Detectors {
+		re, err := regexp.Compile(d.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("secret detector %q: %w", d.Name, err)
+		}
+		s.names = append(s.names, d.Name)
+		s.patterns = append(s.patterns, re)
+	}
+	return s, nil
+}
+
+// Check runs the rule against text; see CheckSecrets.
+func (s *Secrets) Check(text string) string {
+	return s.Find(text).Message
+}
+
+// Find runs the rule against text like Check and locates the secret.
+func (s *Secrets) Find(text string) Finding {
+	for i, re := range s.patterns {
+		if loc := re.FindStringIndex(text); loc != nil && loc[1] > loc[0] {
+			return Finding{
+				Message: fmt.Sprintf("log message contains a hard-coded secret (%s %s)",
+					s.names[i], maskSecret(text[loc[0]:loc[1]])),
+				Start: loc[0],
+				End:   loc[1],
+			}
+		}
+	}
+
+	if s.threshold <= 0 {
+		return noFinding
+	}
+	for start := 0; start < len(text); {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		if isSecretDelimiter(r) {
+			start += size
+			continue
+		}
+		end := start
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if isSecretDelimiter(r) {
+				break
+			}
+			end += size
+		}
+		tok := text[start:end]
+		if len(tok) >= s.minLength && looksRandom(tok) {
+			if e := shannonEntropy(tok); e >= s.threshold {
+				return Finding{
+					Message: fmt.Sprintf(
+						"log message contains a high-entropy string that looks like a secret (%s, %.1f bits/char)",
+						maskSecret(tok), e,
+					),
+					Start: start,
+					End:   end,
+				}
+			}
+		}
+		start = end
+	}
+	return noFinding
+}
+
+// isSecretDelimiter splits text into candidate tokens. The characters used by
+// base64, hex and URL-safe encodings are kept together.
+func isSecretDelimiter(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	case r == '+', r == '/', r == '=', r == '_', r == '-', r == '.':
+		return false
+	}
+	return true
+}
+
+// uuidRe matches canonical UUIDs, which are random but not secret.
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ulidRe matches ULIDs (26 characters of Crockford base32), which are
+// sortable identifiers rather than secrets.
+var ulidRe = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+
+// looksRandom filters out tokens that are long but obviously not secrets:
+// tokens without both letters and digits (words, numbers), paths, UUIDs,
+// ULIDs and lowercase names split by "/", ".", "-" or "_", such as module
+// paths, hostnames and Kubernetes pod names.
+func looksRandom(tok string) bool {
+	if strings.HasPrefix(tok, "/") || strings.HasPrefix(tok, ".") || uuidRe.MatchString(tok) || ulidRe.MatchString(tok) {
+		return false
+	}
+	hasLetter, hasDigit, hasUpper, hasSeparator := false, false, false, false
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		switch {
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case c >= 'a' && c <= 'z':
+			hasLetter = true
+		case c >= 'A' && c <= 'Z':
+			hasLetter, hasUpper = true, true
+		case c == '/', c == '.', c == '-', c == '_':
+			hasSeparator = true
+		}
+	}
+	if hasSeparator && !hasUpper && !strings.ContainsAny(tok, "+=") {
+		return false
+	}
+	return hasLetter && hasDigit
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per byte.
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	n := float64(len(s))
+	e := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// maskSecret keeps only a short prefix of a secret so that diagnostics do not
+// spread the credential further, e.g. into CI logs.
+func maskSecret(s string) string {
+	const keep = 4
+	if len(s) <= keep {
+		return fmt.Sprintf("%q", strings.Repeat("*", len(s)))
+	}
+	return fmt.Sprintf("%q", s[:keep]+"…")
+}
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SplitIdentifier splits a Go identifier or keyword into lower-cased
+// camelCase and snake_case segments:
+//
+//	userPassword  → user, password
+//	APIKey        → api, key
+//	api_key       → api, key
+//	oauth2Token   → oauth, 2, token
+func SplitIdentifier(id string) []string {
+	var segs []string
+	runes := []rune(id)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			segs = append(segs, strings.ToLower(string(runes[start:end])))
+		}
+		start = end
+	}
+
+	for i, r := range runes {
+		if isNotIdentRune(r) || r == '_' {
+			flush(i)
+			start = i + 1
+			continue
+		}
+		if i == start {
+			continue
+		}
+		prev := runes[i-1]
+		switch {
+		case unicode.IsLower(prev) && unicode.IsUpper(r):
+			// userName → user | Name
+			flush(i)
+		case unicode.IsUpper(prev) && unicode.IsUpper(r) &&
+			i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// APIKey → API | Key
+			flush(i)
+		case unicode.IsDigit(prev) != unicode.IsDigit(r):
+			// oauth2 → oauth | 2
+			flush(i)
+		}
+	}
+	flush(len(runes))
+	return segs
+}
+
+// isNotIdentRune reports whether r cannot be part of a Go identifier.
+func isNotIdentRune(r rune) bool {
+	return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// segmentText is the haystack searched by the keyword matcher: the segments
+// of a sequence of units (message words or identifiers), each unit written
+// without separators and consecutive units separated by NUL so that no match
+// can span two of them.
+type segmentText struct {
+	text string
+	segs []segment
+}
+
+// segment is the byte range [start, end) of one segment within text, and the
+// index of the unit it belongs to.
+type segment struct {
+	start, end int
+	unit       int
+}
+
+func newSegmentText(units [][]string) *segmentText {
+	var b strings.Builder
+	st := &segmentText{}
+	for u, segs := range units {
+		if u > 0 {
+			b.WriteByte(0)
+		}
+		for _, seg := range segs {
+			start := b.Len()
+			b.WriteString(seg)
+			st.segs = append(st.segs, segment{start: start, end: b.Len(), unit: u})
+		}
+	}
+	st.text = b.String()
+	return st
+}
+
+// segmentAt returns the index of the segment containing byte offset off.
+func (st *segmentText) segmentAt(off int) int {
+	return sort.Search(len(st.segs), func(i int) bool { return st.segs[i].end > off })
+}
+
+// unitEnd returns the index of the last segment of the unit containing
+// segment i.
+func (st *segmentText) unitEnd(i int) int {
+	for i+1 < len(st.segs) && st.segs[i+1].unit == st.segs[i].unit {
+		i++
+	}
+	return i
+}
+
+// sourceRange maps the byte range [start, end) of a match in text back to
+// the source the segments were taken from, given the source range of every
+// segment. Segments spelled with as many bytes as in the source map byte for
+// byte; others map to their whole source range.
+func (st *segmentText) sourceRange(spans [][2]int, start, end int) (int, int) {
+	first, last := st.segmentAt(start), st.segmentAt(end-1)
+	from, to := spans[first][0], spans[last][1]
+	if seg := st.segs[first]; spans[first][1]-spans[first][0] == seg.end-seg.start {
+		from += start - seg.start
+	}
+	if seg := st.segs[last]; spans[last][1]-spans[last][0] == seg.end-seg.start {
+		to -= seg.end - end
+	}
+	return from, to
+}
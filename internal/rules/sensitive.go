@@ -3,6 +3,7 @@ package rules
 import (
 	"fmt"
 	"strings"
+	"unicode"
 )
 
 func tokenizeWords(s string) []string {
@@ -71,87 +72,342 @@ func stripStringLiterals(expr string) string {
 	return b.String()
 }
 
+// defaultSafeNextWords lists words that commonly follow tokens like
+// "token"/"auth" in a non-sensitive status-style message (e.g. "token
+// validated"). This is a pragmatic false-positive reducer: if a sensitive
+// keyword appears as a standalone word and is immediately followed by one of
+// these, we do not flag.
+var defaultSafeNextWords = []string{
+	"ok",
+	"success",
+	"successful",
+	"succeeded",
+	"failed",
+	"failure",
+	"error",
+	"invalid",
+	"missing",
+	"present",
+	"enabled",
+	"disabled",
+	"created",
+	"generated",
+	"refreshed",
+	"expired",
+	"validated",
+	"completed",
+	"revoked",
+	"rotated",
+	"updated",
+	"authorized",
+	"unauthorized",
+
+	// Qualifiers that turn a keyword into metadata about it, mostly seen as
+	// trailing identifier segments: sessionCount, tokenTTL, authProvider.
+	"count",
+	"id",
+	"ids",
+	"len",
+	"length",
+	"size",
+	"num",
+	"min",
+	"max",
+	"ttl",
+	"timeout",
+	"expiry",
+	"duration",
+	"type",
+	"kind",
+	"name",
+	"policy",
+	"manager",
+	"store",
+	"provider",
+	"service",
+	"handler",
+}
+
+// DefaultSafeNextWords returns a copy of the built-in list of words that
+// suppress a keyword match when they directly follow it, either as the next
+// word of the message text or as the next segment of an identifier.
+func DefaultSafeNextWords() []string {
+	return append([]string(nil), defaultSafeNextWords...)
+}
+
 // CheckSensitive verifies that a log message (or the combined string formed by
 // concatenating string literals and variable references) does not contain
 // keywords that indicate potentially sensitive data.
 //
-// keywords is the list of case-insensitive substrings to look for. Callers
+// keywords is the list of case-insensitive keywords to look for. Callers
 // should pass cfg.SensitiveKeywords which may be extended by the user's config.
 //
 // fullExpr is the full source representation of the argument expression
 // (e.g. `"user password: " + password`). We check both the resolved literal
 // text and the raw expression so that we catch variable names like `apiKey`.
 func CheckSensitive(msg, fullExpr string, keywords []string) string {
-	lower := strings.ToLower(msg)
-	lowerExpr := strings.ToLower(fullExpr)
-	tokens := tokenizeWords(lower)
-
-	// Some words commonly follow tokens like "token"/"auth" in a non-sensitive
-	// status-style message (e.g. "token validated"). This is a pragmatic
-	// false-positive reducer: if a sensitive keyword appears as a standalone
-	// word and is immediately followed by one of these, we do not flag.
-	safeNext := map[string]struct{}{
-		"ok":           {},
-		"success":      {},
-		"successful":   {},
-		"succeeded":    {},
-		"failed":       {},
-		"failure":      {},
-		"error":        {},
-		"invalid":      {},
-		"missing":      {},
-		"present":      {},
-		"enabled":      {},
-		"disabled":     {},
-		"created":      {},
-		"generated":    {},
-		"refreshed":    {},
-		"expired":      {},
-		"validated":    {},
-		"completed":    {},
-		"revoked":      {},
-		"rotated":      {},
-		"updated":      {},
-		"authorized":   {},
-		"unauthorized": {},
+	return CheckSensitiveWith(msg, fullExpr, keywords, defaultSafeNextWords)
+}
+
+// CheckSensitiveWith is like CheckSensitive but uses safeNext instead of the
+// built-in list of words that may follow a keyword in the message text
+// without triggering the rule.
+//
+// Each call compiles the keywords anew; use NewSensitive when checking many
+// messages against the same configuration.
+func CheckSensitiveWith(msg, fullExpr string, keywords, safeNext []string) string {
+	return NewSensitive(keywords, safeNext, nil).Check(msg, fullExpr)
+}
+
+// MatchMode controls how a keyword is matched against the segments of a
+// message word or identifier; see SplitIdentifier for how segments are formed.
+type MatchMode int
+
+const (
+	// MatchSegment requires the keyword to cover whole segments: "token"
+	// matches jwtToken and auth_token but not tokenizer. A trailing plural
+	// "s" on the last segment is accepted (userPasswords).
+	MatchSegment MatchMode = iota
+	// MatchPrefix requires the keyword to start at a segment boundary but
+	// lets it end anywhere: "auth" matches authorization and authToken.
+	MatchPrefix
+	// MatchSubstring matches anywhere, ignoring segment boundaries.
+	MatchSubstring
+)
+
+// ParseMatchMode converts a configuration value ("segment", "prefix" or
+// "substring") into a MatchMode.
+func ParseMatchMode(s string) (MatchMode, bool) {
+	switch s {
+	case "segment":
+		return MatchSegment, true
+	case "prefix":
+		return MatchPrefix, true
+	case "substring":
+		return MatchSubstring, true
+	}
+	return MatchSegment, false
+}
+
+// Sensitive is a precompiled form of the sensitive-data rule. It is
+// immutable after construction and safe for concurrent use.
+//
+// Message words and identifiers found in the argument expression are split
+// into camelCase and snake_case segments, and every keyword is matched on
+// whole segments or segment sequences according to its MatchMode. This keeps
+// apiKey and api_key matching "api_key" while authorName, sessionCount or
+// secretaryID no longer match "auth", "session" or "secret".
+//
+// All keywords are compiled into a single Aho-Corasick automaton, so checking
+// a message costs one scan of the message text and one scan of the argument
+// expression regardless of how many keywords are configured.
+type Sensitive struct {
+	// keywords holds the lower-cased keywords in their original order. A
+	// lower index wins when several keywords match, as does a message-text
+	// match over an expression match of the same keyword.
+	keywords []string
+	modes    []MatchMode
+	m        *matcher
+	// uses[id] lists the keywords that compiled to pattern id.
+	uses     [][]int
+	safeNext map[string]struct{}
+}
+
+// NewSensitive compiles the sensitive-data rule for the given keywords and
+// safe next words (see CheckSensitiveWith). modes overrides the match mode of
+// individual keywords, keyed case-insensitively; all other keywords use
+// MatchSegment.
+func NewSensitive(keywords, safeNext []string, modes map[string]MatchMode) *Sensitive {
+	s := &Sensitive{
+		keywords: make([]string, len(keywords)),
+		modes:    make([]MatchMode, len(keywords)),
+		safeNext: make(map[string]struct{}, len(safeNext)),
+	}
+	lowerModes := make(map[string]MatchMode, len(modes))
+	for kw, mode := range modes {
+		lowerModes[strings.ToLower(kw)] = mode
 	}
 
-	for _, kw := range keywords {
+	var patterns []string
+	ids := make(map[string]int)
+	for i, kw := range keywords {
 		kw = strings.ToLower(kw)
+		s.keywords[i] = kw
+		s.modes[i] = lowerModes[kw]
 
-		// Check the message text itself, but match by word/tokens rather than raw
-		// substring. This avoids noisy matches like keyword "auth" inside
-		// "authenticated".
-		for i, tok := range tokens {
-			if tok != kw {
-				continue
-			}
-			if i+1 < len(tokens) {
-				if _, ok := safeNext[tokens[i+1]]; ok {
-					break
-				}
+		// Keywords are matched against segments joined without separators,
+		// so "api_key" and "apiKey" both compile to "apikey".
+		p := strings.Join(SplitIdentifier(kw), "")
+		id, ok := ids[p]
+		if !ok {
+			id = len(patterns)
+			ids[p] = id
+			patterns = append(patterns, p)
+			s.uses = append(s.uses, nil)
+		}
+		s.uses[id] = append(s.uses[id], i)
+	}
+	s.m = newMatcher(patterns)
+
+	for _, w := range safeNext {
+		s.safeNext[strings.ToLower(w)] = struct{}{}
+	}
+	return s
+}
+
+// Check runs the rule against msg and fullExpr; see CheckSensitive.
+func (s *Sensitive) Check(msg, fullExpr string) string {
+	return s.Find(msg, fullExpr).Message
+}
+
+// Find runs the rule like Check and locates the keyword: its byte range in
+// msg, or the identifier of fullExpr that contains it.
+func (s *Sensitive) Find(msg, fullExpr string) Finding {
+	best, bestInExpr := len(s.keywords), false
+	bestStart, bestEnd, bestIdent := -1, -1, ""
+
+	// Check the message text itself, matching by word segments rather than
+	// raw substring. This avoids noisy matches like keyword "auth" inside
+	// "authenticated". A safe word may be the next word of the message.
+	words, spans := messageWords(msg)
+	st := newSegmentText(words)
+	s.scan(st, true, func(kw, start, end int) {
+		if kw < best {
+			best = kw
+			bestStart, bestEnd = st.sourceRange(spans, start, end)
+		}
+	})
+
+	// Check identifiers in the raw source expression to catch variable names.
+	// e.g.  log.Info("token: " + jwtToken)  → segments "jwt", "token".
+	if best > 0 {
+		ids := strings.FieldsFunc(stripStringLiterals(fullExpr), isNotIdentRune)
+		idents := make([][]string, len(ids))
+		for i, id := range ids {
+			idents[i] = SplitIdentifier(id)
+		}
+		st := newSegmentText(idents)
+		s.scan(st, false, func(kw, start, _ int) {
+			if kw < best {
+				best, bestInExpr = kw, true
+				bestIdent = ids[st.segs[st.segmentAt(start)].unit]
 			}
-			return fmt.Sprintf(
+		})
+	}
+
+	switch {
+	case best == len(s.keywords):
+		return noFinding
+	case bestInExpr:
+		return Finding{
+			Message: fmt.Sprintf(
+				"log message may contain sensitive data (keyword %q found in argument expression)",
+				s.keywords[best],
+			),
+			Start:   -1,
+			End:     -1,
+			Keyword: s.keywords[best],
+			Ident:   bestIdent,
+		}
+	default:
+		return Finding{
+			Message: fmt.Sprintf(
 				"log message may contain sensitive data (keyword %q found in message text)",
-				kw,
-			)
+				s.keywords[best],
+			),
+			Start:   bestStart,
+			End:     bestEnd,
+			Keyword: s.keywords[best],
 		}
+	}
+}
 
-		// Check the raw source expression to catch variable names.
-		// e.g.  log.Info("token: " + jwtToken)  → lowerExpr contains "jwttoken"
-		// We strip spaces from the expression to normalise camelCase matches:
-		// "apiKey" → "apikey" matches the keyword "apikey".
-		exprNoStrings := stripStringLiterals(lowerExpr)
-		normalized := strings.ReplaceAll(exprNoStrings, "_", "")
-		normalized = strings.ReplaceAll(normalized, " ", "")
-		kwNorm := strings.ReplaceAll(kw, "_", "")
-
-		if strings.Contains(normalized, kwNorm) {
-			return fmt.Sprintf(
-				"log message may contain sensitive data (keyword %q found in argument expression)",
-				kw,
-			)
+// messageWords splits msg into words of lower-case letters, digits and
+// underscores, like tokenizeWords on the lower-cased message, and each word
+// into its snake_case segments. spans holds the byte range of every segment
+// in msg, in order.
+func messageWords(msg string) (words [][]string, spans [][2]int) {
+	var word []string
+	var seg strings.Builder
+	segStart := 0
+	flushSeg := func(end int) {
+		if seg.Len() > 0 {
+			word = append(word, seg.String())
+			spans = append(spans, [2]int{segStart, end})
+			seg.Reset()
+		}
+	}
+	flushWord := func(end int) {
+		flushSeg(end)
+		if len(word) > 0 {
+			words = append(words, word)
+			word = nil
+		}
+	}
+	for i, r := range msg {
+		switch c := unicode.ToLower(r); {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			if seg.Len() == 0 {
+				segStart = i
+			}
+			seg.WriteRune(c)
+		case c == '_':
+			flushSeg(i)
+		default:
+			flushWord(i)
 		}
 	}
-	return ""
+	flushWord(len(msg))
+	return words, spans
+}
+
+// isSafe reports whether segment i is one of the safe next words.
+func (s *Sensitive) isSafe(st *segmentText, i int) bool {
+	_, ok := s.safeNext[st.text[st.segs[i].start:st.segs[i].end]]
+	return ok
+}
+
+// scan reports the index of every keyword that matches st according to its
+// match mode and is not directly followed by a safe word, together with the
+// byte range of the match in st.text. acrossUnits allows the safe word to be
+// the first segment of the following unit, which is how message words relate
+// to each other; for identifiers a safe final segment suppresses the match
+// instead.
+func (s *Sensitive) scan(st *segmentText, acrossUnits bool, found func(kw, start, end int)) {
+	s.m.scan(st.text, func(start, end int, id int32) bool {
+		first := st.segmentAt(start)
+		last := st.segmentAt(end - 1)
+		startOK := st.segs[first].start == start
+		endOK := st.segs[last].end == end
+		plural := !endOK && st.segs[last].end == end+1 && st.text[end] == 's'
+
+		safe := false
+		if next := last + 1; next < len(st.segs) &&
+			(acrossUnits || st.segs[next].unit == st.segs[last].unit) {
+			safe = s.isSafe(st, next)
+		}
+		if !safe && !acrossUnits {
+			// An identifier names whatever its final segment names, so
+			// bearerTokenTTL is a TTL even though "token" is not safe.
+			tail := st.unitEnd(last)
+			safe = tail > last && s.isSafe(st, tail)
+		}
+
+		for _, kw := range s.uses[id] {
+			switch s.modes[kw] {
+			case MatchSegment:
+				if !startOK || !(endOK || plural) {
+					continue
+				}
+			case MatchPrefix:
+				if !startOK {
+					continue
+				}
+			}
+			if !safe {
+				found(kw, start, end)
+			}
+		}
+		return true
+	})
 }
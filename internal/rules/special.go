@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // specialCharSet is the set of ASCII punctuation/symbol characters that are
@@ -23,32 +24,55 @@ const defaultForbiddenASCII = "!@#$%^&*+=|\\<>?`~;'\""
 // considers safe (sourced from the user's config file). Any character present
 // in allowedExtra is excluded from the check.
 func CheckSpecialChars(msg, allowedExtra string) string {
-	forbidden := buildForbiddenSet(allowedExtra)
+	return NewSpecial(allowedExtra).Check(msg)
+}
 
-	for i, r := range msg {
-		_ = i
+// Special is a precompiled form of the special-characters rule. It is
+// immutable after construction and safe for concurrent use.
+type Special struct {
+	forbidden map[rune]struct{}
+}
+
+// NewSpecial compiles the special-characters rule; see CheckSpecialChars for
+// the meaning of allowedExtra.
+func NewSpecial(allowedExtra string) *Special {
+	return &Special{forbidden: buildForbiddenSet(allowedExtra)}
+}
+
+// Check runs the rule against msg; see CheckSpecialChars.
+func (s *Special) Check(msg string) string {
+	return s.Find(msg).Message
+}
 
+// Find runs the rule against msg like Check and locates the offending
+// character or sequence.
+func (s *Special) Find(msg string) Finding {
+	for i, r := range msg {
 		// Emoji are encoded in Unicode ranges above U+1F000.
 		// Also catch Miscellaneous Symbols (U+2600–U+26FF) and
 		// Dingbats (U+2700–U+27BF).
 		if isEmoji(r) {
-			return fmt.Sprintf("log message contains emoji or special Unicode symbol (rune %q)", r)
+			return Finding{
+				Message: fmt.Sprintf("log message contains emoji or special Unicode symbol (rune %q)", r),
+				Start:   i,
+				End:     i + utf8.RuneLen(r),
+			}
 		}
 
 		// For ASCII punctuation, check against the forbidden set.
 		if r <= unicode.MaxASCII && unicode.IsPunct(r) || unicode.IsSymbol(r) {
-			if _, bad := forbidden[r]; bad {
-				return fmt.Sprintf("log message contains forbidden special character %q", r)
+			if _, bad := s.forbidden[r]; bad {
+				return Finding{
+					Message: fmt.Sprintf("log message contains forbidden special character %q", r),
+					Start:   i,
+					End:     i + utf8.RuneLen(r),
+				}
 			}
 		}
 	}
 
 	// Detect repeated punctuation like "!!!" or "..." that pollutes log output.
-	if diag := checkRepeatedPunctuation(msg); diag != "" {
-		return diag
-	}
-
-	return ""
+	return findRepeatedPunctuation(msg)
 }
 
 // buildForbiddenSet returns a set built from defaultForbiddenASCII minus any
@@ -77,13 +101,17 @@ func isEmoji(r rune) bool {
 		(r >= 0xFE00 && r <= 0xFE0F) // Variation Selectors
 }
 
-// checkRepeatedPunctuation detects patterns like "!!!" or "..." which indicate
+// findRepeatedPunctuation detects patterns like "!!!" or "..." which indicate
 // emphasis that has no place in structured log messages.
-func checkRepeatedPunctuation(msg string) string {
+func findRepeatedPunctuation(msg string) Finding {
 	for _, seq := range []string{"...", "!!", "??", "***"} {
-		if strings.Contains(msg, seq) {
-			return fmt.Sprintf("log message contains repeated punctuation %q", seq)
+		if i := strings.Index(msg, seq); i >= 0 {
+			return Finding{
+				Message: fmt.Sprintf("log message contains repeated punctuation %q", seq),
+				Start:   i,
+				End:     i + len(seq),
+			}
 		}
 	}
-	return ""
+	return noFinding
 }
@@ -0,0 +1,301 @@
+package rules
+
+import (
+	"cmp"
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// dictionary is the embedded English word list of the spelling rule: one
+// lowercase word per line, drawn from the documentation of the Go toolchain
+// and extended with the vocabulary of typical services (orders, payments,
+// queues, databases, networks). American and British spellings are both
+// listed ("initialized" and "initialised", "canceling" and "cancelling"), so
+// neither is "fixed" into the other.
+//
+//go:embed words.txt
+var dictionary string
+
+// minSpellingWordLength is the length, in runes, below which words are not
+// checked: short words are mostly abbreviations and have too many close
+// matches to suggest anything useful.
+const minSpellingWordLength = 4
+
+// maxSpellingSuggestions bounds the suggestions listed in a diagnostic.
+const maxSpellingSuggestions = 3
+
+// SpellingIssue is a misspelled word of a message.
+type SpellingIssue struct {
+	// Word is the word as written and Offset its byte offset in the message.
+	Word   string
+	Offset int
+	// Suggestions lists the closest dictionary words, best first.
+	Suggestions []string
+	Message     string
+	// Fixed is the message with the word replaced, set only when there is a
+	// single suggestion.
+	Fixed string
+}
+
+// CheckSpelling verifies the words of a constant log message against the
+// embedded dictionary and the extra words, and returns the first misspelling.
+func CheckSpelling(msg string, extra []string) string {
+	issues := NewSpelling(extra).Check(msg)
+	if len(issues) == 0 {
+		return ""
+	}
+	return issues[0].Message
+}
+
+// Spelling is a precompiled form of the spelling rule. It is immutable after
+// construction and safe for concurrent use.
+type Spelling struct {
+	words map[string]bool
+	// byLength groups the dictionary by word length in runes, so candidates
+	// are only compared with words of a similar length.
+	byLength map[int][]string
+}
+
+// NewSpelling compiles the spelling rule for the embedded dictionary plus
+// extra, the project's own words. Extra words are case-insensitive.
+func NewSpelling(extra []string) *Spelling {
+	s := &Spelling{words: make(map[string]bool), byLength: make(map[int][]string)}
+	add := func(w string) {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w == "" || s.words[w] {
+			return
+		}
+		s.words[w] = true
+		n := utf8.RuneCountInString(w)
+		s.byLength[n] = append(s.byLength[n], w)
+	}
+	for _, w := range strings.Split(dictionary, "\n") {
+		add(w)
+	}
+	for _, w := range extra {
+		add(w)
+	}
+	return s
+}
+
+// Check returns every misspelled word of msg that has close dictionary
+// matches. Words with no close match are assumed to be names or jargon and
+// are left alone, as are identifiers, paths, URLs, placeholders and words
+// shorter than four letters.
+func (s *Spelling) Check(msg string) []SpellingIssue {
+	var issues []SpellingIssue
+	for _, w := range spellingWords(msg) {
+		lower := strings.ToLower(w.text)
+		if s.words[lower] {
+			continue
+		}
+		suggestions := s.suggest(lower)
+		if len(suggestions) == 0 {
+			continue
+		}
+		if unicode.IsUpper([]rune(w.text)[0]) {
+			for i, sg := range suggestions {
+				suggestions[i] = strings.ToUpper(sg[:1]) + sg[1:]
+			}
+		}
+
+		issue := SpellingIssue{Word: w.text, Offset: w.offset, Suggestions: suggestions}
+		if len(suggestions) == 1 {
+			issue.Message = fmt.Sprintf("log message contains misspelled word %q (did you mean %q?)", w.text, suggestions[0])
+			issue.Fixed = msg[:w.offset] + suggestions[0] + msg[w.offset+len(w.text):]
+		} else {
+			quoted := make([]string, len(suggestions))
+			for i, sg := range suggestions {
+				quoted[i] = fmt.Sprintf("%q", sg)
+			}
+			issue.Message = fmt.Sprintf("log message contains misspelled word %q (did you mean one of %s?)",
+				w.text, strings.Join(quoted, ", "))
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// suggest returns the dictionary words closest to word: at most one edit
+// away for words of up to five letters and two edits away for longer ones.
+func (s *Spelling) suggest(word string) []string {
+	n := utf8.RuneCountInString(word)
+	maxDist := 1
+	if n > 5 {
+		maxDist = 2
+	}
+
+	best := maxDist + 1
+	var found []string
+	for l := n - maxDist; l <= n+maxDist; l++ {
+		for _, cand := range s.byLength[l] {
+			d := EditDistance(word, cand)
+			switch {
+			case d < best:
+				best, found = d, []string{cand}
+			case d == best:
+				found = append(found, cand)
+			}
+		}
+	}
+	rankSuggestions(word, found)
+	if len(found) > maxSpellingSuggestions {
+		found = found[:maxSpellingSuggestions]
+	}
+	return found
+}
+
+// rankSuggestions orders candidates at the same edit distance from word by
+// how likely they are what the author meant: first those that only double or
+// undouble a letter or swap two adjacent ones, the most common typing slips
+// ("stoped" for "stopped"), then those sharing a longer prefix with word,
+// then alphabetically.
+func rankSuggestions(word string, candidates []string) {
+	slices.SortFunc(candidates, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(typoRank(word, a), typoRank(word, b)),
+			cmp.Compare(commonPrefixLen(word, b), commonPrefixLen(word, a)),
+			cmp.Compare(a, b),
+		)
+	})
+}
+
+// typoRank is 0 when cand differs from word by a doubled letter or by two
+// swapped adjacent letters and 1 otherwise.
+func typoRank(word, cand string) int {
+	short, long := word, cand
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	switch {
+	case len(long) == len(short)+1:
+		i := commonPrefixLen(short, long)
+		if i > 0 && long[i] == long[i-1] && long[:i]+long[i+1:] == short {
+			return 0
+		}
+	case len(long) == len(short):
+		i := commonPrefixLen(short, long)
+		if i+1 < len(long) && short[i] == long[i+1] && short[i+1] == long[i] && short[i+2:] == long[i+2:] {
+			return 0
+		}
+	}
+	return 1
+}
+
+// commonPrefixLen returns the length in bytes of the common prefix of a and
+// b.
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// spellingWord is a checkable word of a message.
+type spellingWord struct {
+	text   string
+	offset int
+}
+
+// spellingWords splits msg into the words the spelling rule checks. A
+// whitespace-separated field is skipped entirely when it looks like code or
+// data rather than prose: identifiers (snake_case, camelCase, ACRONYMS),
+// paths, URLs, e-mail addresses, key=value pairs, format verbs and {holes},
+// and anything with digits or non-ASCII letters. Hyphenated fields are
+// checked word by word.
+func spellingWords(msg string) []spellingWord {
+	var words []spellingWord
+	for start := 0; start < len(msg); {
+		r, size := utf8.DecodeRuneInString(msg[start:])
+		if unicode.IsSpace(r) {
+			start += size
+			continue
+		}
+		end := start
+		for end < len(msg) {
+			r, size := utf8.DecodeRuneInString(msg[end:])
+			if unicode.IsSpace(r) {
+				break
+			}
+			end += size
+		}
+		field, offset := msg[start:end], start
+		start = end
+
+		trimmed := strings.TrimLeft(field, `"'([`)
+		offset += len(field) - len(trimmed)
+		trimmed = strings.TrimRight(trimmed, `"')],.;:!?`)
+		trimmed = strings.TrimSuffix(trimmed, "'s")
+		if !isProseField(trimmed) {
+			continue
+		}
+
+		// tokenizeWords only splits lowercase text; the field is ASCII, so
+		// byte offsets are the same in both spellings.
+		lower := strings.ToLower(trimmed)
+		pos := 0
+		for _, tok := range tokenizeWords(lower) {
+			i := strings.Index(lower[pos:], tok) + pos
+			pos = i + len(tok)
+			if len(tok) >= minSpellingWordLength {
+				words = append(words, spellingWord{text: trimmed[i:pos], offset: offset + i})
+			}
+		}
+	}
+	return words
+}
+
+// isProseField reports whether a trimmed field consists of ASCII letters,
+// possibly hyphenated, with at most the first letter in upper case.
+func isProseField(field string) bool {
+	if field == "" {
+		return false
+	}
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		switch {
+		case c >= 'a' && c <= 'z', c == '-':
+		case c >= 'A' && c <= 'Z':
+			if i > 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// EditDistance returns the optimal string alignment distance between a and
+// b: the number of insertions, deletions, substitutions and transpositions
+// of adjacent letters that turn one into the other. It backs the suggestions
+// of the spelling rule and of configuration errors.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,76 @@
+package rules
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SplitFormat splits a printf-style format string into the literal text
+// around its verbs, so that len(fragments) == verbs+1. "%%" is kept as a
+// literal percent sign. It reports false for formats whose operands cannot be
+// matched to verbs one by one: explicit argument indexes ("%[1]d"), widths or
+// precisions taken from operands ("%*d") and truncated verbs.
+func SplitFormat(format string) (fragments []string, verbs int, ok bool) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			b.WriteByte('%')
+			continue
+		}
+		// Flags, width and precision.
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) || format[i] == '*' || format[i] == '[' {
+			return nil, 0, false
+		}
+		// The verb itself may be any rune.
+		_, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		fragments = append(fragments, b.String())
+		b.Reset()
+		verbs++
+	}
+	return append(fragments, b.String()), verbs, true
+}
+
+// StaticMessage joins the constant fragments left over when the dynamic
+// operands of a message move to attributes. Each fragment loses the spaces
+// and separators around it; a key=value label before a removed value is
+// dropped entirely, since the attribute key takes its place. The non-empty
+// fragments are joined by single spaces:
+//
+//	"user ", " logged in"       → "user logged in"
+//	"query failed: ", ""        → "query failed"
+//	"login user=", ", addr=", "" → "login"
+func StaticMessage(fragments []string) string {
+	words := make([]string, 0, len(fragments))
+	for i, f := range fragments {
+		f = strings.TrimLeft(f, " ,;")
+		if i < len(fragments)-1 {
+			if label := strings.TrimRight(f, " "); strings.HasSuffix(label, "=") {
+				f = label[:strings.LastIndexByte(label, ' ')+1]
+			}
+		}
+		f = strings.TrimRight(f, " :=,;")
+		if f != "" {
+			words = append(words, f)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// AttrKey derives a snake_case attribute key from a Go identifier:
+//
+//	id      → id
+//	userID  → user_id
+//	APIKey  → api_key
+func AttrKey(ident string) string {
+	return strings.Join(SplitIdentifier(ident), "_")
+}
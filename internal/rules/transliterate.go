@@ -0,0 +1,148 @@
+package rules
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Names of the built-in transliteration tables.
+const (
+	TableCyrillic = "cyrillic"
+	TableGreek    = "greek"
+	TableLatin    = "latin"
+)
+
+// TransliterationTableNames returns the names of the built-in
+// transliteration tables, all of which are used by default.
+func TransliterationTableNames() []string {
+	return []string{TableCyrillic, TableGreek, TableLatin}
+}
+
+// transliterationTables maps lowercase runes to their Latin spelling. Upper
+// case runes are looked up in lower case and the result is capitalised.
+var transliterationTables = map[string]map[rune]string{
+	// Russian, Ukrainian and Belarusian letters, following the common
+	// passport-style romanisation.
+	TableCyrillic: {
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+		'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+		'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+		'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+	},
+	// Modern Greek, including the accented vowels.
+	TableGreek: {
+		'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+		'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+		'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+		'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+		'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y", 'ώ': "o",
+		'ϊ': "i", 'ϋ': "y", 'ΐ': "i", 'ΰ': "y",
+	},
+	// Latin letters with diacritics and ligatures.
+	TableLatin: {
+		'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+		'æ': "ae",
+		'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+		'ď': "d", 'đ': "d", 'ð': "d",
+		'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+		'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+		'ĥ': "h", 'ħ': "h",
+		'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+		'ĵ': "j", 'ķ': "k",
+		'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+		'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+		'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+		'œ': "oe",
+		'ŕ': "r", 'ŗ': "r", 'ř': "r",
+		'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ș': "s", 'ß': "ss",
+		'ţ': "t", 'ť': "t", 'ŧ': "t", 'ț': "t", 'þ': "th",
+		'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+		'ŵ': "w",
+		'ý': "y", 'ÿ': "y", 'ŷ': "y",
+		'ź': "z", 'ż': "z", 'ž': "z",
+	},
+}
+
+// Transliterator spells the letters the english rule rejects with Latin
+// ones, which is the fix offered by the rule. It is immutable after
+// construction and safe for concurrent use.
+type Transliterator struct {
+	table map[rune]string
+}
+
+// NewTransliterator combines the named built-in tables with custom entries,
+// which map single characters to their spelling and take precedence over the
+// tables. Unknown table names are ignored; config files are validated when
+// loaded.
+func NewTransliterator(tables []string, custom map[string]string) *Transliterator {
+	t := &Transliterator{table: make(map[rune]string)}
+	for _, name := range tables {
+		for r, s := range transliterationTables[name] {
+			t.table[r] = s
+		}
+	}
+	for from, to := range custom {
+		if r, size := utf8.DecodeRuneInString(from); size == len(from) && size > 0 {
+			t.table[unicode.ToLower(r)] = to
+		}
+	}
+	return t
+}
+
+// Transliterate returns msg with every character the english rule e does
+// not allow spelled in Latin and reports whether the result passes the rule.
+// It fails when such a character has no entry in the tables, such as a Han
+// ideograph, or when the result would be blank: the fix must never leave an
+// empty message behind.
+//
+// Capital letters keep their case: "Ошибка" gives "Oshibka", and letters
+// spelled with several Latin ones are capitalised entirely inside words in
+// capitals, so "ЩИТ" gives "SHCHIT".
+func (t *Transliterator) Transliterate(msg string, e *English) (string, bool) {
+	runes := []rune(msg)
+	var b strings.Builder
+	b.Grow(len(msg))
+	for i, r := range runes {
+		var prev rune
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if e.allowed(r, prev) {
+			b.WriteRune(r)
+			continue
+		}
+		if unicode.Is(unicode.Inherited, r) {
+			// A combining mark on a letter the rule does not allow: the
+			// spelling of the letter replaces both.
+			continue
+		}
+		lower := unicode.ToLower(r)
+		s, ok := t.table[lower]
+		if !ok {
+			return "", false
+		}
+		if lower != r && s != "" {
+			if isUpperAt(runes, i-1) || isUpperAt(runes, i+1) {
+				s = strings.ToUpper(s)
+			} else {
+				first, size := utf8.DecodeRuneInString(s)
+				s = string(unicode.ToUpper(first)) + s[size:]
+			}
+		}
+		b.WriteString(s)
+	}
+
+	out := b.String()
+	if strings.TrimSpace(out) == "" || e.Offending(out) >= 0 {
+		return "", false
+	}
+	return out, true
+}
+
+// isUpperAt reports whether runes[i] exists and is an upper case letter.
+func isUpperAt(runes []rune, i int) bool {
+	return i >= 0 && i < len(runes) && unicode.IsUpper(runes[i])
+}
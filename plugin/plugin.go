@@ -22,7 +22,6 @@ package main
 
 import (
 	"github.com/Wladim1r/loglinter/internal/analyzer"
-	"github.com/Wladim1r/loglinter/internal/config"
 	"golang.org/x/tools/go/analysis"
 )
 
@@ -35,12 +34,16 @@ type analyzerPlugin struct{}
 
 // GetAnalyzers returns the list of analyzers provided by this plugin.
 // golangci-lint calls this method after loading the plugin.
+//
+// Configuration is read from .loglinter.yaml in the current working directory
+// when the analyzer runs; an invalid file fails the run instead of being
+// replaced by defaults. The cross-package analyzer of the duplicate and
+// eventid rules is only added when that configuration, or one in a
+// subdirectory, enables one of them.
 func (analyzerPlugin) GetAnalyzers() []*analysis.Analyzer {
-	// Load configuration from the current working directory; fall back to
-	// defaults if the file is absent or cannot be parsed.
-	cfg, err := config.Load(".loglinter.yaml")
-	if err != nil {
-		cfg = config.DefaultConfig()
+	analyzers := []*analysis.Analyzer{analyzer.Analyzer}
+	if analyzer.CrossPackageRulesEnabled("", ".") {
+		analyzers = append(analyzers, analyzer.CrossPackageAnalyzer)
 	}
-	return []*analysis.Analyzer{analyzer.NewAnalyzer(cfg)}
+	return analyzers
 }